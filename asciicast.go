@@ -0,0 +1,160 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Recording of panes in asciicast v2 format:
+// https://docs.asciinema.org/manual/asciicast/v2/
+
+package tmux
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// Options of the pane recording.
+type RecordOptions struct {
+	Title string            // Title of the recording
+	Env   map[string]string // Environment variables saved in the header, e.g. TERM
+}
+
+// Header of asciicast v2 file.
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Records output of the pane started with Pane.Record.
+type Recorder struct {
+	w      io.Writer
+	out    *outputStream
+	start  time.Time
+	last   float64 // Time of the last event, timestamps never go back
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Starts recording of the pane output to w in asciicast v2 format. The header
+// contains the current size of the pane. Recording continues until Stop is
+// called or ctx is done. Events are timestamped when tmux receives the output
+// from the pane, not when it is delivered to the recorder.
+func (p *Pane) Record(ctx context.Context, w io.Writer, opts RecordOptions) (*Recorder, error) {
+	var size struct {
+		Width  int `tmux:"pane_width"`
+		Height int `tmux:"pane_height"`
+	}
+	if err := p.server.QueryDisplay(ctx, p.target(), &size); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	out, err := p.openOutput(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r := &Recorder{w: w, out: out, start: time.Now(), cancel: cancel, done: make(chan struct{})}
+	header, err := json.Marshal(asciicastHeader{
+		Version:   2,
+		Width:     size.Width,
+		Height:    size.Height,
+		Timestamp: r.start.Unix(),
+		Title:     opts.Title,
+		Env:       opts.Env,
+	})
+	if err == nil {
+		_, err = w.Write(append(header, '\n'))
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go r.record()
+	return r, nil
+}
+
+// Writes output events until the output stream is closed.
+func (r *Recorder) record() {
+	defer close(r.done)
+	defer r.cancel()
+
+	var pending []byte // Incomplete UTF-8 sequence from the previous chunk
+	var at time.Time   // Time of the last chunk
+	for {
+		chunks, err := r.out.next()
+		for _, c := range chunks {
+			var data []byte
+			data, pending = splitUTF8(append(pending, c.data...))
+			at = c.time
+			if werr := r.writeEvent(at, data); werr != nil {
+				r.err = werr
+				return
+			}
+		}
+		if err != nil {
+			// Recording stopped with Stop or ctx is not an error
+			if err != io.EOF && err != context.Canceled && err != context.DeadlineExceeded {
+				r.err = err
+			}
+			break
+		}
+	}
+	if len(pending) > 0 {
+		if err := r.writeEvent(at, pending); err != nil {
+			r.err = err
+		}
+	}
+}
+
+// Writes the output event printed at given time.
+func (r *Recorder) writeEvent(at time.Time, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	// Output buffered by tmux before the start or reordered by clock
+	// adjustments is not moved back in time
+	t := at.Sub(r.start).Seconds()
+	if t < r.last {
+		t = r.last
+	}
+	r.last = t
+	event, err := json.Marshal([]interface{}{t, "o", string(data)})
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(event, '\n'))
+	return err
+}
+
+// Stops the recording and returns an error of reading the pane output or
+// writing the recording, if any. The output delivered by tmux before Stop is
+// written to the recording first.
+func (r *Recorder) Stop() error {
+	r.out.drain()
+	<-r.done
+	return r.err
+}
+
+// Splits the data to the part ending with complete UTF-8 sequences and the
+// incomplete sequence at the end.
+func splitUTF8(data []byte) ([]byte, []byte) {
+	// Look for the start of the last sequence, which is at most UTFMax bytes long
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i], data[i:]
+			}
+			break
+		}
+	}
+	return data, nil
+}
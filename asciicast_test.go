@@ -0,0 +1,97 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitUTF8(t *testing.T) {
+	cases := []struct {
+		data, complete, rest string
+	}{
+		{"abc", "abc", ""},
+		{"aé", "aé", ""},
+		{"a\xc3", "a", "\xc3"},
+		{"a\xe6\xbc", "a", "\xe6\xbc"},
+		{"\xff", "\xff", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		complete, rest := splitUTF8([]byte(c.data))
+		if string(complete) != c.complete || string(rest) != c.rest {
+			t.Errorf("Unexpected split of %q: %q, %q", c.data, complete, rest)
+		}
+	}
+}
+
+func TestRecorderTimestamps(t *testing.T) {
+	var out bytes.Buffer
+	r := &Recorder{w: &out, start: time.Now()}
+	for _, at := range []time.Time{
+		r.start.Add(-time.Second), // Buffered by tmux before the start
+		r.start.Add(2 * time.Second),
+		r.start.Add(time.Second),
+	} {
+		if err := r.writeEvent(at, []byte("x")); err != nil {
+			t.Fatalf("writeEvent: %s", err)
+		}
+	}
+	expected := "[0,\"o\",\"x\"]\n[2,\"o\",\"x\"]\n[2,\"o\",\"x\"]\n"
+	if out.String() != expected {
+		t.Fatalf("Unexpected events:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestPaneRecord(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var out syncBuffer
+	rec, err := pane.Record(ctx, &out, RecordOptions{Title: "test", Env: map[string]string{"TERM": "screen"}})
+	if err != nil {
+		t.Fatalf("Record: %s", err)
+	}
+	if _, err := pane.Exec(ctx, `printf '%s-%s\n' recorded é`); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	var header asciicastHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("Bad header %q: %s", lines[0], err)
+	}
+	if header.Version != 2 || header.Width != pane.Width || header.Height != pane.Height ||
+		header.Title != "test" || header.Env["TERM"] != "screen" {
+		t.Fatalf("Unexpected header: %+v", header)
+	}
+
+	var output bytes.Buffer
+	last := 0.0
+	for _, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || len(event) != 3 {
+			t.Fatalf("Bad event %q: %v", line, err)
+		}
+		ts, _ := event[0].(float64)
+		if ts < last || event[1] != "o" {
+			t.Fatalf("Unexpected event: %q", line)
+		}
+		last = ts
+		output.WriteString(event[2].(string))
+	}
+	if !strings.Contains(output.String(), "recorded-é\r\n") {
+		t.Fatalf("Output was not recorded: %q", output.String())
+	}
+}
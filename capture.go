@@ -0,0 +1,115 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Capturing of pane contents with capture-pane options.
+
+package tmux
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jubnzv/go-tmux/screen"
+)
+
+// Options of capture-pane command. Zero value captures the visible content of
+// the pane.
+type CaptureOptions struct {
+	// First line to capture: 0 is the first visible line, negative numbers
+	// are lines of the history and "-" is the start of the history.
+	Start string
+	// Last line to capture in the same format, "-" is the end of the visible
+	// content.
+	End string
+
+	EscapeSequences        bool // Include escape sequences for text and background attributes
+	JoinLines              bool // Join wrapped lines and preserve trailing spaces
+	PreserveTrailingSpaces bool // Preserve trailing spaces at the end of lines
+	TrimTrailingPositions  bool // Ignore trailing positions that do not contain a character
+	AlternateScreen        bool // Capture the alternate screen instead of the main one
+	PendingInput           bool // Capture the input not yet processed by the pane
+}
+
+// Returns arguments of capture-pane command.
+func (o CaptureOptions) args(target string) []string {
+	args := []string{"capture-pane", "-p", "-t", target}
+	if o.Start != "" {
+		args = append(args, "-S", o.Start)
+	}
+	if o.End != "" {
+		args = append(args, "-E", o.End)
+	}
+	if o.EscapeSequences {
+		args = append(args, "-e")
+	}
+	if o.JoinLines {
+		args = append(args, "-J")
+	}
+	if o.PreserveTrailingSpaces {
+		args = append(args, "-N")
+	}
+	if o.TrimTrailingPositions {
+		args = append(args, "-T")
+	}
+	if o.AlternateScreen {
+		args = append(args, "-a")
+	}
+	if o.PendingInput {
+		args = append(args, "-P")
+	}
+	return args
+}
+
+// Content of the pane captured with CaptureWithOptions.
+type CaptureResult struct {
+	Content string // Captured content as printed by tmux
+}
+
+// Returns the captured lines without trailing newlines.
+func (c CaptureResult) Lines() []string {
+	if c.Content == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(c.Content, "\n"), "\n")
+}
+
+// Captures content of the pane with given options.
+func (p *Pane) CaptureWithOptions(opts CaptureOptions) (CaptureResult, error) {
+	return p.CaptureWithOptionsContext(context.Background(), opts)
+}
+
+// Same as CaptureWithOptions, but aborts the tmux command when ctx is done.
+func (p *Pane) CaptureWithOptionsContext(ctx context.Context, opts CaptureOptions) (CaptureResult, error) {
+	out, _, err := p.server.run(ctx, opts.args(p.target()))
+	if err != nil {
+		return CaptureResult{}, err
+	}
+	return CaptureResult{Content: out}, nil
+}
+
+// Returns the visible content of the pane with colors, attributes and the
+// cursor position.
+func (p *Pane) Screen() (*screen.Screen, error) {
+	return p.ScreenContext(context.Background())
+}
+
+// Same as Screen, but aborts the tmux command when ctx is done.
+func (p *Pane) ScreenContext(ctx context.Context) (*screen.Screen, error) {
+	var state struct {
+		Width   int `tmux:"pane_width"`
+		Height  int `tmux:"pane_height"`
+		CursorX int `tmux:"cursor_x"`
+		CursorY int `tmux:"cursor_y"`
+	}
+	if err := p.server.QueryDisplay(ctx, p.target(), &state); err != nil {
+		return nil, err
+	}
+	result, err := p.CaptureWithOptionsContext(ctx, CaptureOptions{EscapeSequences: true})
+	if err != nil {
+		return nil, err
+	}
+
+	s := screen.Parse(result.Content, state.Width, state.Height)
+	s.CursorX, s.CursorY = state.CursorX, state.CursorY
+	return s, nil
+}
@@ -0,0 +1,94 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jubnzv/go-tmux/screen"
+)
+
+func TestCaptureOptionsArgs(t *testing.T) {
+	args := CaptureOptions{Start: "-", End: "-1", EscapeSequences: true, JoinLines: true}.args("%1")
+	expected := []string{"capture-pane", "-p", "-t", "%1", "-S", "-", "-E", "-1", "-e", "-J"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Incorrect arguments (expected %v got %v)", expected, args)
+	}
+}
+
+func TestCaptureResultLines(t *testing.T) {
+	lines := CaptureResult{Content: "a\n\nb\n"}.Lines()
+	if !reflect.DeepEqual(lines, []string{"a", "", "b"}) {
+		t.Fatalf("Unexpected lines: %q", lines)
+	}
+	if lines := (CaptureResult{}).Lines(); len(lines) != 0 {
+		t.Fatalf("Unexpected lines: %q", lines)
+	}
+}
+
+func TestPaneCaptureWithOptions(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := pane.Exec(ctx, `printf '\033[31m%s\033[0m\n' red`); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+
+	result, err := pane.CaptureWithOptions(CaptureOptions{Start: "-", EscapeSequences: true})
+	if err != nil {
+		t.Fatalf("CaptureWithOptions: %s", err)
+	}
+	found := false
+	for _, line := range result.Lines() {
+		if strings.HasPrefix(line, "\x1b[31mred") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Colored line was not captured: %q", result.Content)
+	}
+
+	// There is no alternate screen in the shell
+	if _, err := pane.CaptureWithOptions(CaptureOptions{AlternateScreen: true}); err == nil {
+		t.Fatalf("Alternate screen was captured")
+	}
+}
+
+func TestPaneScreen(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := pane.Exec(ctx, `printf 'plain \033[1;31m%s%s\033[0m\n' re d`); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+
+	sc, err := pane.Screen()
+	if err != nil {
+		t.Fatalf("Screen: %s", err)
+	}
+	if sc.Width != pane.Width || sc.Height != pane.Height {
+		t.Fatalf("Unexpected screen size: %dx%d", sc.Width, sc.Height)
+	}
+	x, y, ok := sc.Find("red")
+	if !ok {
+		t.Fatalf("Text was not found: %q", sc.String())
+	}
+	if c := sc.Cell(x, y); c.Fg != screen.Indexed(screen.Red) || !c.Has(screen.AttrBold) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if c := sc.Cell(0, y); c.Fg != (screen.Color{}) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if sc.CursorY <= y {
+		t.Fatalf("Unexpected cursor position: %d, %d", sc.CursorX, sc.CursorY)
+	}
+}
@@ -0,0 +1,102 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Clients attached to the server and their control.
+
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Represents a tmux client - a terminal attached to the session:
+// https://github.com/tmux/tmux/wiki/Getting-Started#the-tmux-server-and-clients
+type Client struct {
+	Name        string    `tmux:"client_name"`     // Name of the client, usually the same as Tty
+	Tty         string    `tmux:"client_tty"`      // Pseudo terminal of the client
+	Pid         int       `tmux:"client_pid"`      // PID of the client process
+	SessionName string    `tmux:"client_session"`  // Name of the attached session
+	Termname    string    `tmux:"client_termname"` // Terminal name of the client
+	Width       int       `tmux:"client_width"`    // Width of the client in cells
+	Height      int       `tmux:"client_height"`   // Height of the client in cells
+	ReadOnly    bool      `tmux:"client_readonly"` // True if the client is read-only
+	Activity    time.Time `tmux:"client_activity"` // Time of the last activity of the client
+
+	server *Server // Server this client is attached to
+}
+
+// Lists all clients attached to this server.
+func (s *Server) ListClients() ([]Client, error) {
+	return s.ListClientsContext(context.Background())
+}
+
+// Same as ListClients, but aborts the tmux command when ctx is done.
+func (s *Server) ListClientsContext(ctx context.Context) ([]Client, error) {
+	clients := []Client{}
+	if err := s.QueryList(ctx, &clients, "list-clients"); err != nil {
+		return nil, err
+	}
+	for i := range clients {
+		clients[i].server = s
+	}
+	return clients, nil
+}
+
+// Detaches the client from its session.
+func (c *Client) Detach() error {
+	return c.DetachContext(context.Background())
+}
+
+// Same as Detach, but aborts the tmux command when ctx is done.
+func (c *Client) DetachContext(ctx context.Context) error {
+	_, _, err := c.server.run(ctx, []string{"detach-client", "-t", c.Name})
+	return err
+}
+
+// Switches the client to the session with given name.
+func (c *Client) SwitchTo(session string) error {
+	return c.SwitchToContext(context.Background(), session)
+}
+
+// Same as SwitchTo, but aborts the tmux command when ctx is done.
+func (c *Client) SwitchToContext(ctx context.Context, session string) error {
+	args := []string{"switch-client", "-c", c.Name, "-t", session}
+	if _, _, err := c.server.run(ctx, args); err != nil {
+		return err
+	}
+	c.SessionName = session
+	return nil
+}
+
+// Redraws the client.
+func (c *Client) Refresh() error {
+	return c.RefreshContext(context.Background())
+}
+
+// Same as Refresh, but aborts the tmux command when ctx is done.
+func (c *Client) RefreshContext(ctx context.Context) error {
+	_, _, err := c.server.run(ctx, []string{"refresh-client", "-t", c.Name})
+	return err
+}
+
+// Sets the size of the client. tmux supports it only for control mode clients,
+// sizes of other clients are defined by their terminals.
+func (c *Client) Resize(width, height int) error {
+	return c.ResizeContext(context.Background(), width, height)
+}
+
+// Same as Resize, but aborts the tmux command when ctx is done.
+func (c *Client) ResizeContext(ctx context.Context, width, height int) error {
+	args := []string{
+		"refresh-client",
+		"-t", c.Name,
+		"-C", fmt.Sprintf("%dx%d", width, height),
+	}
+	if _, _, err := c.server.run(ctx, args); err != nil {
+		return err
+	}
+	c.Width, c.Height = width, height
+	return nil
+}
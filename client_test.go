@@ -0,0 +1,62 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClients(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	if _, err := server.NewSession("test-client-other"); err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	control, err := server.NewControlClient(session.Name)
+	if err != nil {
+		t.Fatalf("NewControlClient: %s", err)
+	}
+	defer control.Close()
+
+	clients, err := server.ListClients()
+	if err != nil {
+		t.Fatalf("ListClients: %s", err)
+	}
+	if len(clients) != 1 {
+		t.Fatalf("Expected a single client, got %d", len(clients))
+	}
+	client := clients[0]
+	if client.SessionName != session.Name || client.Pid == 0 {
+		t.Fatalf("Unexpected client: %+v", client)
+	}
+
+	if err := client.SwitchTo("test-client-other"); err != nil {
+		t.Fatalf("SwitchTo: %s", err)
+	}
+	if err := client.Resize(100, 30); err != nil {
+		t.Fatalf("Resize: %s", err)
+	}
+	clients, err = server.ListClients()
+	if err != nil || len(clients) != 1 {
+		t.Fatalf("ListClients: %v", err)
+	}
+	if clients[0].SessionName != "test-client-other" {
+		t.Fatalf("Client was not switched: %+v", clients[0])
+	}
+	// tmux doesn't report height of control clients
+	if clients[0].Width != 100 {
+		t.Fatalf("Client was not resized: %+v", clients[0])
+	}
+
+	if err := client.Detach(); err != nil {
+		t.Fatalf("Detach: %s", err)
+	}
+	select {
+	case <-control.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Client was not detached")
+	}
+}
@@ -1,25 +1,50 @@
 // The MIT License (MIT)
 // Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
 
-// TODO: TMUX_TMPDIR
-
 package tmux
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"os/exec"
 	"syscall"
 )
 
+// Executor runs tmux commands on behalf of the Server and all sessions,
+// windows and panes obtained from it. Implement it to inject a fake, add
+// logging or route commands to another transport.
+type Executor interface {
+	// Runs tmux with given arguments and returns stdout and stderr output.
+	// Implementations should abort the command and return ctx.Err() when ctx
+	// is done.
+	Run(ctx context.Context, args []string) (string, string, error)
+}
+
+// Default executor that runs the tmux binary found in $PATH.
+type DefaultExecutor struct{}
+
+// Runs tmux with given arguments using RunCmdContext. UTF-8 is forced with -u
+// flag, because without UTF-8 locale tmux replaces control characters in the
+// output, including the separator of format values (see formatSeparator).
+func (DefaultExecutor) Run(ctx context.Context, args []string) (string, string, error) {
+	return RunCmdContext(ctx, append([]string{"-u"}, args...))
+}
+
 // Wrapper to tmux CLI that execute command with given arguments and returns
 // stdout and stderr output.
 func RunCmd(args []string) (string, string, error) {
+	return RunCmdContext(context.Background(), args)
+}
+
+// Same as RunCmd, but kills the tmux process and returns ctx.Err() when ctx
+// is done.
+func RunCmdContext(ctx context.Context, args []string) (string, string, error) {
 	tmux, err := exec.LookPath("tmux")
 	if err != nil {
 		return "", "", err
 	}
-	cmd := exec.Command(tmux, args...)
+	cmd := exec.CommandContext(ctx, tmux, args...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -28,6 +53,11 @@ func RunCmd(args []string) (string, string, error) {
 	err = cmd.Run()
 	outStr, errStr := string(stdout.Bytes()), string(stderr.Bytes())
 
+	// The process was killed because of cancellation.
+	if err != nil && ctx.Err() != nil {
+		return outStr, errStr, ctx.Err()
+	}
+
 	return outStr, errStr, err
 }
 
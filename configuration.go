@@ -7,9 +7,11 @@
 package tmux
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strings"
+
+	"github.com/jubnzv/go-tmux/layout"
 )
 
 type Configuration struct {
@@ -27,6 +29,39 @@ func (c *Configuration) checkInput() error {
 			msg := fmt.Sprintf("Session %s doesn't contain any windows!", s.Name)
 			return errors.New(msg)
 		}
+
+		for _, w := range s.Windows {
+			if err := checkWindowLayout(w); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Checks that custom layout of the window is correct and matches its panes
+func checkWindowLayout(w Window) error {
+	if !isCustomLayout(w.Layout) {
+		return nil
+	}
+
+	l, err := layout.Parse(w.Layout)
+	if err != nil {
+		return fmt.Errorf("Window %s: %s", w.Name, err)
+	}
+	if err := l.Validate(); err != nil {
+		return fmt.Errorf("Window %s: %s", w.Name, err)
+	}
+
+	// The first pane is created even if there are no panes in configuration
+	panes := len(w.Panes)
+	if panes == 0 {
+		panes = 1
+	}
+	if len(l.Panes()) != panes {
+		return fmt.Errorf("Window %s: layout contains %d panes, but window has %d",
+			w.Name, len(l.Panes()), panes)
 	}
 
 	return nil
@@ -37,6 +72,11 @@ func (c *Configuration) checkInput() error {
 // session with same names exists. Otherwise existing sessions/windows will be
 // replaced with the new ones.
 func (c *Configuration) Apply() error {
+	return c.ApplyContext(context.Background())
+}
+
+// Same as Apply, but aborts the tmux command when ctx is done.
+func (c *Configuration) ApplyContext(ctx context.Context) error {
 	if c.Server == nil {
 		return errors.New("Server was not initialized")
 	}
@@ -51,6 +91,9 @@ func (c *Configuration) Apply() error {
 
 	// Initialize sessions
 	for _, s := range c.Sessions {
+		// Further commands on this session will target the configured server
+		s.server = c.Server
+
 		// Set initial window for a new session
 		initial_window := s.Windows[0]
 
@@ -75,10 +118,10 @@ func (c *Configuration) Apply() error {
 			"-s", s.Name,
 		}
 		args = append(args, args_start_dir...)
-		_, err_out, err_exec := RunCmd(args)
+		_, _, err_exec := c.Server.run(ctx, args)
 		if err_exec != nil {
 			// It's okay, if session already exists.
-			if !strings.Contains(err_out, "exit status 1") {
+			if !errors.Is(err_exec, ErrDuplicateSession) {
 				return err_exec
 			}
 		}
@@ -108,7 +151,7 @@ func (c *Configuration) Apply() error {
 				"-t", winId,
 			}
 			args = append(args, args_start_dir...)
-			_, _, err_exec := RunCmd(args)
+			_, _, err_exec := c.Server.run(ctx, args)
 			if err_exec != nil {
 				return err_exec
 			}
@@ -122,7 +165,7 @@ func (c *Configuration) Apply() error {
 						"-t", winId,
 						"-c", windowStartDirectory,
 					}
-					_, _, err_exec := RunCmd(args)
+					_, _, err_exec := c.Server.run(ctx, args)
 					if err_exec != nil {
 						return err_exec
 					}
@@ -132,7 +175,7 @@ func (c *Configuration) Apply() error {
 			// Select layout if defined
 			if len(w.Layout) != 0 {
 				args = []string{"select-layout", "-t", winId, w.Layout}
-				_, _, err_exec := RunCmd(args)
+				_, _, err_exec := c.Server.run(ctx, args)
 				if err_exec != nil {
 					return err_exec
 				}
@@ -0,0 +1,70 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"testing"
+
+	"github.com/jubnzv/go-tmux/layout"
+)
+
+func TestConfigurationBadLayout(t *testing.T) {
+	session := &Session{Name: "test-bad-layout"}
+	w := Window{Name: "w", Panes: []Pane{{}, {}}}
+	w.SetLayout("bb62,159x48,0,0{79x48,0,0,79x48,80,0}")
+	session.AddWindow(w)
+	conf := Configuration{Server: new(Server), Sessions: []*Session{session}}
+	if err := conf.checkInput(); err != nil {
+		t.Fatalf("Correct layout was rejected: %s", err)
+	}
+
+	session.Windows[0].Panes = append(session.Windows[0].Panes, Pane{})
+	if err := conf.checkInput(); err == nil {
+		t.Fatalf("Layout with wrong number of panes was accepted")
+	}
+
+	session.Windows[0].SetLayout("0000,159x48,0,0{79x48,0,0,79x48,80,0}")
+	if err := conf.checkInput(); err == nil {
+		t.Fatalf("Layout with bad checksum was accepted")
+	}
+
+	// Other layouts are checked by tmux
+	for _, l := range []string{LayoutTiled, "even-h", "main-horizontal-mirrored"} {
+		session.Windows[0].SetLayout(l)
+		if err := conf.checkInput(); err != nil {
+			t.Fatalf("Layout %s was rejected: %s", l, err)
+		}
+	}
+}
+
+func TestConfigurationCustomLayout(t *testing.T) {
+	server := NewServer("", "go-tmux-"+t.Name(), nil)
+	defer killServer(server)
+
+	custom, err := layout.ParseBody("80x24,0,0{20x24,0,0,59x24,21,0}")
+	if err != nil {
+		t.Fatalf("ParseBody: %s", err)
+	}
+	session := &Session{Name: "test-custom-layout"}
+	w := Window{Name: "custom", Panes: []Pane{{}, {}}}
+	w.SetCustomLayout(custom)
+	session.AddWindow(w)
+	conf := Configuration{Server: server, Sessions: []*Session{session}}
+	if err := conf.Apply(); err != nil {
+		t.Fatalf("Apply: %s", err)
+	}
+
+	windows, err := session.ListWindows()
+	if err != nil || len(windows) != 1 {
+		t.Fatalf("ListWindows: %v", err)
+	}
+	current, err := windows[0].ParseLayout()
+	if err != nil {
+		t.Fatalf("ParseLayout: %s", err)
+	}
+	panes := current.Panes()
+	if len(panes) != 2 || panes[0].Width != 20 || panes[1].Width != 59 {
+		t.Fatalf("Layout was not applied: %s", current)
+	}
+}
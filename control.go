@@ -0,0 +1,299 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Persistent tmux client working in the control mode:
+// https://github.com/tmux/tmux/wiki/Control-Mode
+
+package tmux
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Returned by ControlClient when tmux reports an error for the command. The
+// error message is returned as stderr output.
+var errControlCommand = errors.New("Command failed")
+
+// Represents a tmux client started in the control mode (tmux -C). It keeps a
+// single connection to the server and sends commands through it, so it is
+// much faster than starting a new tmux process for each command.
+//
+// ControlClient implements Executor, so it can be used as a transport for
+// Server:
+//
+//	client, err := server.NewControlClient("")
+//	server.Executor = client
+type ControlClient struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	ready chan struct{} // Closed when the client is attached
+	done  chan struct{} // Closed when the client exits
+
+	mu       sync.Mutex       // Protects stdin and all fields below
+	pending  []*controlResult // Commands waiting for the reply, in order they were sent
+	attached bool             // Whether the attach command was finished
+	exitErr  error            // Reason of exit of the client
+	exitOut  string           // Error output of the client command
+
+	subMu sync.Mutex                 // Protects subs
+	subs  map[*subscription]struct{} // Subscribers of notifications
+}
+
+// Reply to a command list sent by the control client.
+type controlResult struct {
+	lines  []string
+	failed bool
+	blocks int // Number of replies left, one per command of the list
+	done   chan struct{}
+}
+
+// Starts a new control client attached to the session with given name on this
+// server. If session is empty, the most recently used session is attached.
+func (s *Server) NewControlClient(session string) (*ControlClient, error) {
+	tmux, err := exec.LookPath("tmux")
+	if err != nil {
+		return nil, newTmuxError([]string{"-C"}, "", err)
+	}
+
+	// UTF-8 is forced for the same reason as in DefaultExecutor
+	args := append([]string{"-u"}, s.socketArgs()...)
+	args = append(args, "-C", "attach-session")
+	if session != "" {
+		args = append(args, "-t", session)
+	}
+	c := &ControlClient{
+		cmd:   exec.Command(tmux, args...),
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+		subs:  make(map[*subscription]struct{}),
+	}
+	if c.stdin, err = c.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cmd.Start(); err != nil {
+		return nil, err
+	}
+	go c.readLoop(stdout)
+
+	// Commands sent before attaching are executed without a client, so wait
+	// for the reply to the attach command first.
+	select {
+	case <-c.ready:
+	case <-c.done:
+	}
+	c.mu.Lock()
+	err, errOut := c.exitErr, c.exitOut
+	c.mu.Unlock()
+	if err != nil {
+		c.Close()
+		return nil, newTmuxError(args, errOut, err)
+	}
+
+	return c, nil
+}
+
+// Runs tmux command with given arguments over the control connection. Flags
+// that select the socket (-L and -S) at the beginning of args are ignored,
+// because the client is already connected to the server. Like on the command
+// line, a ";" argument separates commands of a command list; their outputs
+// are joined.
+//
+// When ctx is done, Run returns ctx.Err() immediately, but the command is not
+// aborted: tmux executes it and the reply is discarded.
+func (c *ControlClient) Run(ctx context.Context, args []string) (string, string, error) {
+	for len(args) >= 2 && (args[0] == "-L" || args[0] == "-S") {
+		args = args[2:]
+	}
+
+	res := &controlResult{blocks: 1, done: make(chan struct{})}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == ";" {
+			quoted[i] = arg
+			res.blocks++
+		} else {
+			quoted[i] = quoteControlArg(arg)
+		}
+	}
+	line := strings.Join(quoted, " ") + "\n"
+
+	c.mu.Lock()
+	if c.exitErr != nil {
+		err, errOut := c.exitErr, c.exitOut
+		c.mu.Unlock()
+		return "", errOut, err
+	}
+	c.pending = append(c.pending, res)
+	if _, err := io.WriteString(c.stdin, line); err != nil {
+		c.pending = c.pending[:len(c.pending)-1]
+		c.mu.Unlock()
+		return "", "", err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-res.done:
+	case <-ctx.Done():
+		return "", "", ctx.Err()
+	case <-c.done:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return "", c.exitOut, c.exitErr
+	}
+
+	out := ""
+	for _, l := range res.lines {
+		out += l + "\n"
+	}
+	if res.failed {
+		return "", out, errControlCommand
+	}
+	return out, "", nil
+}
+
+// Closes the connection and waits for tmux client process to exit.
+func (c *ControlClient) Close() error {
+	c.mu.Lock()
+	err := c.stdin.Close()
+	c.mu.Unlock()
+	<-c.done
+	c.cmd.Wait()
+	return err
+}
+
+// Returns a channel that is closed when the client exits.
+func (c *ControlClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Reads the output of tmux client and dispatches replies to pending commands
+// and notifications to subscribers.
+func (c *ControlClient) readLoop(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var block *controlResult // Reply that is being read
+	var blockTag string      // "time number flags" of the current block
+	var fromClient bool      // Whether the current block is a reply to our command
+	exitReason := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if block != nil {
+			// tmux writes flow control notifications caused by
+			// refresh-client inside its reply.
+			if isFlowControlLine(line) {
+				c.publish(parseEvent(line))
+				continue
+			}
+			if line == "%end "+blockTag || line == "%error "+blockTag {
+				block.failed = strings.HasPrefix(line, "%error")
+				c.finishBlock(block, fromClient)
+				block = nil
+			} else {
+				block.lines = append(block.lines, line)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "%begin "):
+			blockTag = strings.TrimPrefix(line, "%begin ")
+			fields := strings.Fields(blockTag)
+			fromClient = len(fields) == 3 && fields[2] == "1"
+			block = &controlResult{}
+		case line == "%exit" || strings.HasPrefix(line, "%exit "):
+			exitReason = strings.TrimSpace(strings.TrimPrefix(line, "%exit"))
+			c.publish(ExitEvent{Reason: exitReason})
+		case strings.HasPrefix(line, "%"):
+			c.publish(parseEvent(line))
+		}
+	}
+	c.closeSubscriptions()
+
+	c.mu.Lock()
+	if c.exitErr == nil {
+		msg := "Control client exited"
+		if exitReason != "" {
+			msg += ": " + exitReason
+		}
+		c.exitErr = errors.New(msg)
+	}
+	c.pending = nil
+	c.mu.Unlock()
+	close(c.done)
+}
+
+// Returns true if the line is %pause or %continue notification.
+func isFlowControlLine(line string) bool {
+	for _, prefix := range []string{"%pause %", "%continue %"} {
+		if strings.HasPrefix(line, prefix) {
+			_, err := parseId(line[len(prefix):])
+			return err == nil
+		}
+	}
+	return false
+}
+
+// Delivers the reply. The first block that is not a reply to commands of this
+// client is produced by the attach command itself, so an error in it is saved
+// as the reason of exit.
+func (c *ControlClient) finishBlock(block *controlResult, fromClient bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !fromClient {
+		if !c.attached {
+			c.attached = true
+			if block.failed {
+				c.exitOut = strings.Join(block.lines, "\n")
+				c.exitErr = errControlCommand
+			}
+			close(c.ready)
+		}
+		return
+	}
+	if len(c.pending) == 0 {
+		return
+	}
+	res := c.pending[0]
+	res.blocks--
+	if block.failed {
+		// tmux skips the rest of the command list after an error
+		res.lines, res.failed = block.lines, true
+	} else {
+		res.lines = append(res.lines, block.lines...)
+	}
+	if res.failed || res.blocks == 0 {
+		c.pending = c.pending[1:]
+		close(res.done)
+	}
+}
+
+// Quotes command argument for tmux command parser.
+func quoteControlArg(arg string) string {
+	if !strings.ContainsAny(arg, "\n\r") {
+		return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+	}
+
+	// Single quoted strings can't contain escape sequences
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + r.Replace(arg) + `"`
+}
@@ -0,0 +1,129 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Creates a session on a separate server used in control mode tests.
+func createControlServer(t *testing.T) (*Server, Session) {
+	server := NewServer("", "go-tmux-"+t.Name(), nil)
+	session, err := server.NewSession("test-control")
+	if err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	return server, session
+}
+
+// Creates a server with createControlServer and splits its pane to a new one
+// running the command.
+func createShellPane(t *testing.T, command string) (*Server, Pane) {
+	server, session := createControlServer(t)
+	panes, err := session.ListPanes()
+	if err != nil || len(panes) != 1 {
+		killServer(server)
+		t.Fatalf("ListPanes: %v", err)
+	}
+	pane, err := panes[0].Split(SplitOptions{Command: command})
+	if err != nil {
+		killServer(server)
+		t.Fatalf("Split: %s", err)
+	}
+	return server, pane
+}
+
+func TestControlClientExecutor(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	client, err := server.NewControlClient(session.Name)
+	if err != nil {
+		t.Fatalf("NewControlClient: %s", err)
+	}
+	defer client.Close()
+	server.Executor = client
+
+	if _, err := session.NewWindow("test-control-window"); err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	windows, err := session.ListWindows()
+	if err != nil {
+		t.Fatalf("ListWindows: %s", err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("Expected 2 windows, got %d", len(windows))
+	}
+
+	if has, err := server.HasSession("test-control-missing"); has || err != nil {
+		t.Fatalf("HasSession: expected false, got %v (%v)", has, err)
+	}
+	if err := (&Window{Id: 99999, server: server}).Select(); !errors.Is(err, ErrWindowNotFound) {
+		t.Fatalf("Expected %v, got %v", ErrWindowNotFound, err)
+	}
+}
+
+func TestControlClientQuoting(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	client, err := server.NewControlClient(session.Name)
+	if err != nil {
+		t.Fatalf("NewControlClient: %s", err)
+	}
+	defer client.Close()
+
+	for _, msg := range []string{"it's a \"test\"; $HOME \\", "first\nsecond", ""} {
+		out, _, err := client.Run(context.Background(), []string{"display-message", "-p", msg})
+		if err != nil {
+			t.Fatalf("display-message: %s", err)
+		}
+		if out != msg+"\n" {
+			t.Errorf("Expected %q, got %q", msg+"\n", out)
+		}
+	}
+}
+
+func TestControlClientCommandList(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	client, err := server.NewControlClient(session.Name)
+	if err != nil {
+		t.Fatalf("NewControlClient: %s", err)
+	}
+	defer client.Close()
+
+	args := []string{"display-message", "-p", "first", ";", "display-message", "-p", "second"}
+	out, _, err := client.Run(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if out != "first\nsecond\n" {
+		t.Errorf("Unexpected output of command list: %q", out)
+	}
+
+	// The rest of the list is skipped after the error
+	args = []string{"display-message", "-p", "first", ";", "select-window", "-t", "@99999", ";", "display-message", "-p", "third"}
+	if _, errOut, err := client.Run(context.Background(), args); err == nil || errOut == "" {
+		t.Fatalf("Expected error of command list, got %v (%q)", err, errOut)
+	}
+	// Replies to the next commands are not shifted
+	out, _, err = client.Run(context.Background(), []string{"display-message", "-p", "next"})
+	if err != nil || out != "next\n" {
+		t.Fatalf("Unexpected reply after failed list: %q (%v)", out, err)
+	}
+}
+
+func TestControlClientMissingSession(t *testing.T) {
+	server, _ := createControlServer(t)
+	defer killServer(server)
+
+	_, err := server.NewControlClient("test-control-missing")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected %v, got %v", ErrSessionNotFound, err)
+	}
+}
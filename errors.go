@@ -0,0 +1,96 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Typed errors returned by tmux commands.
+
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Sentinel errors that can be checked with errors.Is on errors returned by
+// Server, Session, Window and Pane methods.
+var (
+	ErrSessionNotFound  = errors.New("Session not found")
+	ErrWindowNotFound   = errors.New("Window not found")
+	ErrPaneNotFound     = errors.New("Pane not found")
+	ErrDuplicateSession = errors.New("Duplicate session")
+	ErrNoServer         = errors.New("No tmux server running")
+	ErrTmuxNotInstalled = errors.New("tmux is not installed")
+)
+
+// Patterns of tmux error output mapped to sentinel errors.
+var stderrPatterns = []struct {
+	pattern string
+	err     error
+}{
+	{"can't find session", ErrSessionNotFound},
+	{"session not found", ErrSessionNotFound},
+	{"can't find window", ErrWindowNotFound},
+	{"window not found", ErrWindowNotFound},
+	{"can't find pane", ErrPaneNotFound},
+	{"pane not found", ErrPaneNotFound},
+	{"duplicate session", ErrDuplicateSession},
+	{"no server running", ErrNoServer},
+	{"error connecting to", ErrNoServer},
+}
+
+// Represents a failed tmux command.
+type TmuxError struct {
+	Args     []string // Arguments of the failed command
+	ExitCode int      // Exit code of tmux process or -1 if it is unknown
+	Stderr   string   // Error output of tmux
+	Err      error    // Underlying error returned by Executor
+	kind     error    // One of the sentinel errors or nil
+}
+
+// Creates a new error for the failed command, recognizing the sentinel error
+// from its stderr output.
+func newTmuxError(args []string, stderr string, err error) *TmuxError {
+	e := &TmuxError{
+		Args:     args,
+		ExitCode: -1,
+		Stderr:   stderr,
+		Err:      err,
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.ExitCode = exitErr.ExitCode()
+	}
+
+	if errors.Is(err, exec.ErrNotFound) {
+		e.kind = ErrTmuxNotInstalled
+		return e
+	}
+	for _, p := range stderrPatterns {
+		if strings.Contains(stderr, p.pattern) {
+			e.kind = p.err
+			break
+		}
+	}
+
+	return e
+}
+
+func (e *TmuxError) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("tmux %s: %s", strings.Join(e.Args, " "), msg)
+}
+
+// Returns the underlying error.
+func (e *TmuxError) Unwrap() error {
+	return e.Err
+}
+
+// Reports whether the error matches one of the sentinel errors.
+func (e *TmuxError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
@@ -0,0 +1,62 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestTmuxErrorKinds(t *testing.T) {
+	cases := []struct {
+		stderr string
+		err    error
+	}{
+		{"can't find session: foo\n", ErrSessionNotFound},
+		{"can't find window: @42\n", ErrWindowNotFound},
+		{"can't find pane: %42\n", ErrPaneNotFound},
+		{"duplicate session: foo\n", ErrDuplicateSession},
+		{"no server running on /tmp/tmux-1000/default\n", ErrNoServer},
+		{"error connecting to /tmp/tmux-1000/foo (No such file or directory)\n", ErrNoServer},
+	}
+	for _, c := range cases {
+		err := newTmuxError([]string{"cmd"}, c.stderr, errors.New("exit status 1"))
+		if !errors.Is(err, c.err) {
+			t.Errorf("%q: expected %v", c.stderr, c.err)
+		}
+	}
+
+	err := newTmuxError([]string{"cmd"}, "", &exec.Error{Name: "tmux", Err: exec.ErrNotFound})
+	if !errors.Is(err, ErrTmuxNotInstalled) {
+		t.Errorf("Expected %v, got %v", ErrTmuxNotInstalled, err)
+	}
+	if errors.Is(err, ErrNoServer) {
+		t.Errorf("Unexpected %v", ErrNoServer)
+	}
+}
+
+func TestDuplicateSessionError(t *testing.T) {
+	s := new(Server)
+	if _, err := s.NewSession("test-duplicate-session"); err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	defer sessionsReaper("test-duplicate-session")
+
+	_, err := s.NewSession("test-duplicate-session")
+	if !errors.Is(err, ErrDuplicateSession) {
+		t.Fatalf("Expected %v, got %v", ErrDuplicateSession, err)
+	}
+	var tmuxErr *TmuxError
+	if !errors.As(err, &tmuxErr) || tmuxErr.ExitCode != 1 {
+		t.Fatalf("Expected TmuxError with exit code 1, got %#v", err)
+	}
+}
+
+func TestWindowNotFoundError(t *testing.T) {
+	w := Window{Id: 99999}
+	if err := w.Select(); !errors.Is(err, ErrWindowNotFound) {
+		t.Fatalf("Expected %v, got %v", ErrWindowNotFound, err)
+	}
+}
@@ -0,0 +1,516 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Notifications sent by tmux to control mode clients:
+// https://github.com/tmux/tmux/wiki/Control-Mode#notifications
+
+package tmux
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Represents a notification received from tmux. The concrete type of the event
+// is one of the *Event types defined in this file.
+type Event interface {
+	// Returns the name of the notification without leading '%', for example
+	// "window-add".
+	EventName() string
+}
+
+// The client has detached.
+type ClientDetachedEvent struct {
+	Client string // Name of the client
+}
+
+// The client is now attached to another session.
+type ClientSessionChangedEvent struct {
+	Client      string // Name of the client
+	SessionId   int
+	SessionName string
+}
+
+// The control client is exiting.
+type ExitEvent struct {
+	Reason string // Optional reason of exit
+}
+
+// The layout of a window has changed.
+type LayoutChangeEvent struct {
+	WindowId      int
+	Layout        string // Layout of the window
+	VisibleLayout string // Layout of the window when zoomed
+	Flags         string // Window flags
+}
+
+// The message was printed with display-message.
+type MessageEvent struct {
+	Message string
+}
+
+// The pane has produced output. Sent as %output or, when flow control is
+// enabled, as %extended-output.
+type OutputEvent struct {
+	PaneId int
+	Age    int    // How long the output was buffered by tmux in milliseconds
+	Data   []byte // Decoded output of the pane
+}
+
+// Output of the pane has been paused because the client is too far behind.
+type PauseEvent struct {
+	PaneId int
+}
+
+// Output of the paused pane has been resumed.
+type ContinueEvent struct {
+	PaneId int
+}
+
+// The pane has entered or left a mode (copy mode etc).
+type PaneModeChangedEvent struct {
+	PaneId int
+}
+
+// The paste buffer has been changed.
+type PasteBufferChangedEvent struct {
+	Name string
+}
+
+// The paste buffer has been deleted.
+type PasteBufferDeletedEvent struct {
+	Name string
+}
+
+// The client is now attached to another session.
+type SessionChangedEvent struct {
+	SessionId   int
+	SessionName string
+}
+
+// The session has been renamed.
+type SessionRenamedEvent struct {
+	SessionId   int
+	SessionName string
+}
+
+// The current window of a session has changed.
+type SessionWindowChangedEvent struct {
+	SessionId int
+	WindowId  int
+}
+
+// A session has been created or destroyed.
+type SessionsChangedEvent struct{}
+
+// A window not linked to the attached session has been created.
+type UnlinkedWindowAddEvent struct {
+	WindowId int
+}
+
+// A window not linked to the attached session has been closed.
+type UnlinkedWindowCloseEvent struct {
+	WindowId int
+}
+
+// A window not linked to the attached session has been renamed.
+type UnlinkedWindowRenamedEvent struct {
+	WindowId   int
+	WindowName string
+}
+
+// A window has been linked to the attached session.
+type WindowAddEvent struct {
+	WindowId int
+}
+
+// A window has been closed.
+type WindowCloseEvent struct {
+	WindowId int
+}
+
+// The active pane of a window has changed.
+type WindowPaneChangedEvent struct {
+	WindowId int
+	PaneId   int
+}
+
+// The window has been renamed.
+type WindowRenamedEvent struct {
+	WindowId   int
+	WindowName string
+}
+
+// Notification not known to this library.
+type UnknownEvent struct {
+	Name string // Name of the notification without leading '%'
+	Args string // Rest of the notification line
+}
+
+func (ClientDetachedEvent) EventName() string        { return "client-detached" }
+func (ClientSessionChangedEvent) EventName() string  { return "client-session-changed" }
+func (ContinueEvent) EventName() string              { return "continue" }
+func (ExitEvent) EventName() string                  { return "exit" }
+func (LayoutChangeEvent) EventName() string          { return "layout-change" }
+func (MessageEvent) EventName() string               { return "message" }
+func (OutputEvent) EventName() string                { return "output" }
+func (PaneModeChangedEvent) EventName() string       { return "pane-mode-changed" }
+func (PasteBufferChangedEvent) EventName() string    { return "paste-buffer-changed" }
+func (PasteBufferDeletedEvent) EventName() string    { return "paste-buffer-deleted" }
+func (PauseEvent) EventName() string                 { return "pause" }
+func (SessionChangedEvent) EventName() string        { return "session-changed" }
+func (SessionRenamedEvent) EventName() string        { return "session-renamed" }
+func (SessionWindowChangedEvent) EventName() string  { return "session-window-changed" }
+func (SessionsChangedEvent) EventName() string       { return "sessions-changed" }
+func (UnlinkedWindowAddEvent) EventName() string     { return "unlinked-window-add" }
+func (UnlinkedWindowCloseEvent) EventName() string   { return "unlinked-window-close" }
+func (UnlinkedWindowRenamedEvent) EventName() string { return "unlinked-window-renamed" }
+func (WindowAddEvent) EventName() string             { return "window-add" }
+func (WindowCloseEvent) EventName() string           { return "window-close" }
+func (WindowPaneChangedEvent) EventName() string     { return "window-pane-changed" }
+func (WindowRenamedEvent) EventName() string         { return "window-renamed" }
+func (e UnknownEvent) EventName() string             { return e.Name }
+
+// Parses tmux object id like "$1", "@2" or "%3" to a number.
+func parseId(id string) (int, error) {
+	if len(id) > 0 && strings.ContainsRune("$@%", rune(id[0])) {
+		id = id[1:]
+	}
+	return strconv.Atoi(id)
+}
+
+// Decodes output of the pane in which tmux replaces characters less than ASCII
+// 32 and the \ character with their octal \xxx form.
+func decodeOutput(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
+			out = append(out, (s[i+1]-'0')<<6|(s[i+2]-'0')<<3|(s[i+3]-'0'))
+			i += 3
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return out
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+// Splits the line to n space-separated fields, where the last field contains
+// the rest of the line. Returns false if there are less than n fields.
+func splitEventArgs(line string, n int) ([]string, bool) {
+	fields := strings.SplitN(line, " ", n)
+	return fields, len(fields) == n
+}
+
+// Parses a notification line received from tmux. Notifications that can't be
+// parsed are returned as UnknownEvent.
+func parseEvent(line string) Event {
+	name, args := strings.TrimPrefix(line, "%"), ""
+	if i := strings.IndexByte(name, ' '); i >= 0 {
+		name, args = name[:i], name[i+1:]
+	}
+	unknown := UnknownEvent{Name: name, Args: args}
+
+	// Parses arguments that consist of the id and the name
+	parseIdName := func() (int, string, bool) {
+		f, ok := splitEventArgs(args, 2)
+		if !ok {
+			return 0, "", false
+		}
+		id, err := parseId(f[0])
+		return id, f[1], err == nil
+	}
+	parseSingleId := func() (int, bool) {
+		id, err := parseId(args)
+		return id, err == nil
+	}
+
+	switch name {
+	case "client-detached":
+		return ClientDetachedEvent{Client: args}
+	case "client-session-changed":
+		f, ok := splitEventArgs(args, 3)
+		if !ok {
+			return unknown
+		}
+		id, err := parseId(f[1])
+		if err != nil {
+			return unknown
+		}
+		return ClientSessionChangedEvent{Client: f[0], SessionId: id, SessionName: f[2]}
+	case "continue":
+		if id, ok := parseSingleId(); ok {
+			return ContinueEvent{PaneId: id}
+		}
+	case "exit":
+		return ExitEvent{Reason: args}
+	case "extended-output":
+		// %extended-output %pane age ... : value
+		i := strings.Index(args, " : ")
+		if i < 0 {
+			return unknown
+		}
+		f := strings.Fields(args[:i])
+		if len(f) < 2 {
+			return unknown
+		}
+		id, err1 := parseId(f[0])
+		age, err2 := strconv.Atoi(f[1])
+		if err1 == nil && err2 == nil {
+			return OutputEvent{PaneId: id, Age: age, Data: decodeOutput(args[i+3:])}
+		}
+	case "layout-change":
+		f := strings.Fields(args)
+		if len(f) < 2 {
+			return unknown
+		}
+		id, err := parseId(f[0])
+		if err != nil {
+			return unknown
+		}
+		e := LayoutChangeEvent{WindowId: id, Layout: f[1]}
+		if len(f) > 2 {
+			e.VisibleLayout = f[2]
+		}
+		if len(f) > 3 {
+			e.Flags = f[3]
+		}
+		return e
+	case "message":
+		return MessageEvent{Message: args}
+	case "output":
+		f, ok := splitEventArgs(args, 2)
+		if !ok {
+			return unknown
+		}
+		if id, err := parseId(f[0]); err == nil {
+			return OutputEvent{PaneId: id, Data: decodeOutput(f[1])}
+		}
+	case "pane-mode-changed":
+		if id, ok := parseSingleId(); ok {
+			return PaneModeChangedEvent{PaneId: id}
+		}
+	case "paste-buffer-changed":
+		return PasteBufferChangedEvent{Name: args}
+	case "paste-buffer-deleted":
+		return PasteBufferDeletedEvent{Name: args}
+	case "pause":
+		if id, ok := parseSingleId(); ok {
+			return PauseEvent{PaneId: id}
+		}
+	case "session-changed":
+		if id, n, ok := parseIdName(); ok {
+			return SessionChangedEvent{SessionId: id, SessionName: n}
+		}
+	case "session-renamed":
+		if id, n, ok := parseIdName(); ok {
+			return SessionRenamedEvent{SessionId: id, SessionName: n}
+		}
+	case "session-window-changed":
+		f := strings.Fields(args)
+		if len(f) != 2 {
+			return unknown
+		}
+		sid, err1 := parseId(f[0])
+		wid, err2 := parseId(f[1])
+		if err1 == nil && err2 == nil {
+			return SessionWindowChangedEvent{SessionId: sid, WindowId: wid}
+		}
+	case "sessions-changed":
+		return SessionsChangedEvent{}
+	case "unlinked-window-add":
+		if id, ok := parseSingleId(); ok {
+			return UnlinkedWindowAddEvent{WindowId: id}
+		}
+	case "unlinked-window-close":
+		if id, ok := parseSingleId(); ok {
+			return UnlinkedWindowCloseEvent{WindowId: id}
+		}
+	case "unlinked-window-renamed":
+		if id, n, ok := parseIdName(); ok {
+			return UnlinkedWindowRenamedEvent{WindowId: id, WindowName: n}
+		}
+	case "window-add":
+		if id, ok := parseSingleId(); ok {
+			return WindowAddEvent{WindowId: id}
+		}
+	case "window-close":
+		if id, ok := parseSingleId(); ok {
+			return WindowCloseEvent{WindowId: id}
+		}
+	case "window-pane-changed":
+		f := strings.Fields(args)
+		if len(f) != 2 {
+			return unknown
+		}
+		wid, err1 := parseId(f[0])
+		pid, err2 := parseId(f[1])
+		if err1 == nil && err2 == nil {
+			return WindowPaneChangedEvent{WindowId: wid, PaneId: pid}
+		}
+	case "window-renamed":
+		if id, n, ok := parseIdName(); ok {
+			return WindowRenamedEvent{WindowId: id, WindowName: n}
+		}
+	}
+
+	return unknown
+}
+
+// Delivers events to a single subscriber. Events are queued, so a slow reader
+// never blocks the control client.
+type subscription struct {
+	in     chan Event       // Events sent by the control client
+	out    chan Event       // Events received by the subscriber
+	stop   chan struct{}    // Closed when the subscriber is not interested anymore
+	filter func(Event) bool // Returns false for events that are not queued
+}
+
+func newSubscription(filter func(Event) bool) *subscription {
+	sub := &subscription{
+		filter: filter,
+		in:     make(chan Event),
+		out:    make(chan Event),
+		stop:   make(chan struct{}),
+	}
+	go sub.loop()
+	return sub
+}
+
+func (sub *subscription) loop() {
+	defer close(sub.out)
+
+	queue := []Event{}
+	in := sub.in
+	for in != nil || len(queue) > 0 {
+		var out chan Event
+		var next Event
+		if len(queue) > 0 {
+			out, next = sub.out, queue[0]
+		}
+
+		select {
+		case e, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			queue = append(queue, e)
+		case out <- next:
+			queue = queue[1:]
+		case <-sub.stop:
+			return
+		}
+	}
+}
+
+// Returns a channel that receives notifications of this client and a function
+// that cancels the subscription. The channel is closed after the subscription
+// is cancelled or the client exits.
+//
+// Notifications are queued until they are received, including output of all
+// panes of the attached session. Use SubscribeFilter to skip events that are
+// not needed, so a slow subscriber does not accumulate them.
+func (c *ControlClient) Subscribe() (<-chan Event, func()) {
+	return c.SubscribeFilter(nil)
+}
+
+// Same as Subscribe, but only events for which filter returns true are
+// delivered. A nil filter accepts all events.
+func (c *ControlClient) SubscribeFilter(filter func(Event) bool) (<-chan Event, func()) {
+	sub := newSubscription(filter)
+
+	c.subMu.Lock()
+	if c.subs == nil {
+		// The client has already exited
+		close(sub.in)
+	} else {
+		c.subs[sub] = struct{}{}
+	}
+	c.subMu.Unlock()
+
+	cancelled := false
+	cancel := func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+		if cancelled {
+			return
+		}
+		cancelled = true
+		if _, ok := c.subs[sub]; ok {
+			delete(c.subs, sub)
+			close(sub.in)
+		}
+		close(sub.stop)
+	}
+
+	return sub.out, cancel
+}
+
+// Sends the event to all subscribers.
+func (c *ControlClient) publish(e Event) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for sub := range c.subs {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		select {
+		case sub.in <- e:
+		case <-sub.stop:
+		}
+	}
+}
+
+// Closes all subscriptions after the client exits.
+func (c *ControlClient) closeSubscriptions() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for sub := range c.subs {
+		close(sub.in)
+	}
+	c.subs = nil
+}
+
+// Returns a channel that receives notifications from this server until ctx is
+// done. If Executor of this server is a ControlClient, it is used to receive
+// the notifications. Otherwise, a new control client attached to the most
+// recently used session is started and closed when ctx is done.
+//
+// Output of panes is not delivered: use Pane.Output to read it.
+//
+// Note that tmux sends notifications about windows and panes only for the
+// session the control client is attached to.
+func (s *Server) Subscribe(ctx context.Context) (<-chan Event, error) {
+	client, ok := s.Executor.(*ControlClient)
+	if !ok {
+		var err error
+		if client, err = s.NewControlClient(""); err != nil {
+			return nil, err
+		}
+		// Output is not needed, so tmux should not send it at all. Not
+		// supported by tmux older than 3.2, so errors are ignored.
+		client.Run(ctx, []string{"refresh-client", "-f", "no-output"})
+	}
+
+	events, cancel := client.SubscribeFilter(func(e Event) bool {
+		_, output := e.(OutputEvent)
+		return !output
+	})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-client.Done():
+		}
+		cancel()
+		if !ok {
+			client.Close()
+		}
+	}()
+
+	return events, nil
+}
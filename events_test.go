@@ -0,0 +1,100 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	cases := []struct {
+		line     string
+		expected Event
+	}{
+		{"%window-add @3", WindowAddEvent{WindowId: 3}},
+		{"%window-close @12", WindowCloseEvent{WindowId: 12}},
+		{"%window-renamed @1 my window", WindowRenamedEvent{WindowId: 1, WindowName: "my window"}},
+		{"%session-changed $2 work", SessionChangedEvent{SessionId: 2, SessionName: "work"}},
+		{"%sessions-changed", SessionsChangedEvent{}},
+		{"%layout-change @4 b25d,80x24,0,0,2 b25d,80x24,0,0,2 *",
+			LayoutChangeEvent{WindowId: 4, Layout: "b25d,80x24,0,0,2", VisibleLayout: "b25d,80x24,0,0,2", Flags: "*"}},
+		{"%pane-mode-changed %7", PaneModeChangedEvent{PaneId: 7}},
+		{"%window-pane-changed @1 %5", WindowPaneChangedEvent{WindowId: 1, PaneId: 5}},
+		{"%client-session-changed /dev/pts/3 $1 main", ClientSessionChangedEvent{Client: "/dev/pts/3", SessionId: 1, SessionName: "main"}},
+		{"%exit detached", ExitEvent{Reason: "detached"}},
+		{"%future-event a b", UnknownEvent{Name: "future-event", Args: "a b"}},
+		{"%window-add bad", UnknownEvent{Name: "window-add", Args: "bad"}},
+	}
+	for _, c := range cases {
+		if e := parseEvent(c.line); e != c.expected {
+			t.Errorf("%q: expected %#v got %#v", c.line, c.expected, e)
+		}
+	}
+}
+
+func TestServerSubscribe(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := server.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe: %s", err)
+	}
+
+	// Output of the shell in the new window must not be delivered
+	window, err := session.NewWindow("test-events")
+	if err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	panes, err := window.ListPanes()
+	if err != nil || len(panes) == 0 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	if err := panes[0].RunCommand("echo test-events"); err != nil {
+		t.Fatalf("RunCommand: %s", err)
+	}
+	if _, err := session.NewWindow("test-events-last"); err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for found := 0; found < 2; {
+		select {
+		case e := <-events:
+			switch e := e.(type) {
+			case WindowAddEvent:
+				found++
+			case OutputEvent:
+				t.Fatalf("Unexpected output event: %#v", e)
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for window-add event")
+		}
+	}
+
+	// Channel must be closed after cancellation
+	cancel()
+	for range events {
+	}
+}
+
+func TestParseOutputEvent(t *testing.T) {
+	cases := []struct {
+		line     string
+		expected OutputEvent
+	}{
+		{`%output %1 hi\015\012`, OutputEvent{PaneId: 1, Data: []byte("hi\r\n")}},
+		{`%output %2 a\134b \033[0m`, OutputEvent{PaneId: 2, Data: []byte("a\\b \x1b[0m")}},
+		{`%extended-output %3 120 : x : y`, OutputEvent{PaneId: 3, Age: 120, Data: []byte("x : y")}},
+	}
+	for _, c := range cases {
+		if e := parseEvent(c.line); !reflect.DeepEqual(e, c.expected) {
+			t.Errorf("%q: expected %#v got %#v", c.line, c.expected, e)
+		}
+	}
+}
@@ -74,11 +74,15 @@ func doSave(savePath string, fileName string, verbose bool) error {
 	if err != nil {
 		return err
 	}
-	session := tmux.Session{Name: session_name}
-	windows, err := session.ListWindows()
+	server := new(tmux.Server)
+	snapshot, err := server.Snapshot()
 	if err != nil {
 		return err
 	}
+	session := snapshot.FindSession(session_name)
+	if session == nil {
+		return fmt.Errorf("Can't find session %s", session_name)
+	}
 
 	// Generate filename if not specified
 	if len(fileName) == 0 {
@@ -87,11 +91,11 @@ func doSave(savePath string, fileName string, verbose bool) error {
 
 	// Collect windows configurations
 	windows_conf := []WindowConf{}
-	for _, w := range windows {
+	for _, w := range session.Windows {
 		windows_conf = append(windows_conf, WindowConf{
-			WindowName:     w.Name,
-			StartDirectory: w.StartDirectory})
-		fmt.Println(w.StartDirectory)
+			WindowName:     w.Window.Name,
+			StartDirectory: w.Window.StartDirectory})
+		fmt.Println(w.Window.StartDirectory)
 	}
 
 	// Prepare session configuration
@@ -0,0 +1,117 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Execution of shell commands in panes.
+
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Counter used to make markers of concurrent commands unique.
+var execCounter uint64
+
+// Result of the command executed with Pane.Exec.
+type ExecResult struct {
+	ExitCode int    // Exit status of the command
+	Output   string // Output of the command as displayed in the pane
+}
+
+// Runs the shell command in the pane and waits until it finishes. Returns its
+// exit status and output. The command is typed in the pane, so the pane must
+// be running a POSIX shell waiting for input. Use ctx to set a timeout: when
+// it is done, Exec returns ctx.Err() while the command keeps running.
+//
+// Output is captured from the pane with joined wrapped lines, starting from the
+// line where the command is typed. Output scrolled out of the pane history is
+// lost, so Exec waits until ctx is done for commands that print more lines
+// than the history-limit option allows or clear the history.
+func (p *Pane) Exec(ctx context.Context, command string) (ExecResult, error) {
+	id := fmt.Sprintf("%d_%d", time.Now().UnixNano(), atomic.AddUint64(&execCounter, 1))
+	begin := "__GOTMUX_BEGIN_" + id
+	end := "__GOTMUX_END_" + id
+
+	// Markers are printed in two parts, so the echoed command line never
+	// matches them. The command is evaluated separately to get its exit status
+	// regardless of its syntax.
+	line := fmt.Sprintf("printf '%%s%%s\\n' __GOTMUX_ BEGIN_%s; eval %s; "+
+		"printf '\\n%%s%%s:%%d\\n' __GOTMUX_ END_%s \"$?\"",
+		id, shellQuote(command), id)
+	var before execHistory
+	if err := p.server.QueryDisplay(ctx, p.target(), &before); err != nil {
+		return ExecResult{}, err
+	}
+	if err := p.RunCommandContext(ctx, line); err != nil {
+		return ExecResult{}, err
+	}
+
+	var result ExecResult
+	endRe := regexp.MustCompile(`\n` + end + `:(\d+)\n`)
+	// Lines printed between the query and the capture only delay the result
+	// until the output stops
+	args := func() ([]string, error) {
+		var now execHistory
+		if err := p.server.QueryDisplay(ctx, p.target(), &now); err != nil {
+			return nil, err
+		}
+		return CaptureOptions{Start: before.captureStart(now), JoinLines: true}.args(p.target()), nil
+	}
+	err := p.pollCapture(ctx, args, func(out string) bool {
+		var ok bool
+		result, ok = parseExecOutput("\n"+out, begin, endRe)
+		return ok
+	})
+	return result, err
+}
+
+// Position of the cursor and size of the pane history.
+type execHistory struct {
+	Size    int `tmux:"history_size"`
+	Limit   int `tmux:"history_limit"`
+	CursorY int `tmux:"cursor_y"`
+}
+
+// Returns the start line for capture-pane of the output printed since the
+// cursor was at this position, given the current history. Lines scrolled to
+// the history move up, so the line number becomes negative. If the history is
+// full or cleared, the lines can't be tracked and the whole history is
+// captured.
+func (h execHistory) captureStart(now execHistory) string {
+	if now.Size < h.Size || now.Size >= now.Limit {
+		return "-"
+	}
+	return strconv.Itoa(h.CursorY - (now.Size - h.Size))
+}
+
+// Extracts the output and exit status of the command from the captured pane
+// content. Returns false if the command has not finished yet.
+func parseExecOutput(out string, begin string, endRe *regexp.Regexp) (ExecResult, bool) {
+	i := strings.LastIndex(out, "\n"+begin+"\n")
+	if i < 0 {
+		return ExecResult{}, false
+	}
+	out = out[i+len(begin)+1:]
+	m := endRe.FindStringSubmatchIndex(out)
+	if m == nil {
+		return ExecResult{}, false
+	}
+	code, err := strconv.Atoi(out[m[2]:m[3]])
+	if err != nil {
+		return ExecResult{}, false
+	}
+	// Skip the newline after the begin marker, the newline printed before the
+	// end marker is not included in the match
+	return ExecResult{ExitCode: code, Output: out[1:m[0]]}, true
+}
+
+// Returns the string quoted for POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
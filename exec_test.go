@@ -0,0 +1,73 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseExecOutput(t *testing.T) {
+	endRe := regexp.MustCompile(`\nEND:(\d+)\n`)
+	cases := []struct {
+		out    string
+		output string
+		ok     bool
+	}{
+		{"\n$ cmd\nBEGIN\nabc\n\nEND:2\n$ \n", "abc\n", true},
+		{"\nBEGIN\nabc\nEND:2\n", "abc", true},
+		{"\nBEGIN\n\nEND:2\n", "", true},
+		{"\nBEGIN\nabc\n", "", false},
+		{"\nabc\nEND:2\n", "", false},
+	}
+	for _, c := range cases {
+		result, ok := parseExecOutput(c.out, "BEGIN", endRe)
+		if ok != c.ok || result.Output != c.output || (ok && result.ExitCode != 2) {
+			t.Errorf("Unexpected result for %q: %+v, %v", c.out, result, ok)
+		}
+	}
+}
+
+func TestExecHistoryCaptureStart(t *testing.T) {
+	before := execHistory{Size: 10, Limit: 100, CursorY: 5}
+	cases := []struct {
+		size  int
+		start string
+	}{
+		{10, "5"},
+		{12, "3"},
+		{20, "-5"},
+		{100, "-"}, // Full history
+		{0, "-"},   // Cleared history
+	}
+	for _, c := range cases {
+		if start := before.captureStart(execHistory{Size: c.size, Limit: 100}); start != c.start {
+			t.Errorf("Unexpected start for history size %d (expected %s got %s)", c.size, c.start, start)
+		}
+	}
+}
+
+func TestPaneExec(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := pane.Exec(ctx, `echo "it's"; printf 'a\nb'; exit_code() { return 3; }; exit_code`)
+	if err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+	if result.ExitCode != 3 || result.Output != "it's\na\nb" {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+
+	// Timeout of the command that never finishes
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := pane.Exec(ctx, "sleep 10"); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
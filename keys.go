@@ -0,0 +1,184 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Sending of keys to panes:
+// https://man7.org/linux/man-pages/man1/tmux.1.html#KEY_BINDINGS
+
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Name of the key in tmux format, e.g. "Enter", "C-c" or "a".
+type Key string
+
+const (
+	KeyEnter     Key = "Enter"
+	KeyTab       Key = "Tab"
+	KeyBackTab   Key = "BTab"
+	KeyEscape    Key = "Escape"
+	KeySpace     Key = "Space"
+	KeyBackspace Key = "BSpace"
+	KeyUp        Key = "Up"
+	KeyDown      Key = "Down"
+	KeyLeft      Key = "Left"
+	KeyRight     Key = "Right"
+	KeyHome      Key = "Home"
+	KeyEnd       Key = "End"
+	KeyPageUp    Key = "PPage"
+	KeyPageDown  Key = "NPage"
+	KeyInsert    Key = "IC"
+	KeyDelete    Key = "DC"
+	KeyF1        Key = "F1"
+	KeyF2        Key = "F2"
+	KeyF3        Key = "F3"
+	KeyF4        Key = "F4"
+	KeyF5        Key = "F5"
+	KeyF6        Key = "F6"
+	KeyF7        Key = "F7"
+	KeyF8        Key = "F8"
+	KeyF9        Key = "F9"
+	KeyF10       Key = "F10"
+	KeyF11       Key = "F11"
+	KeyF12       Key = "F12"
+)
+
+// Returns the key pressed with Ctrl, e.g. Ctrl("c") is "C-c".
+func Ctrl(k Key) Key {
+	return "C-" + k
+}
+
+// Returns the key pressed with Meta (Alt), e.g. Meta(KeyLeft) is "M-Left".
+func Meta(k Key) Key {
+	return "M-" + k
+}
+
+// Returns the key pressed with Shift, e.g. Shift(KeyTab) is "S-Tab".
+func Shift(k Key) Key {
+	return "S-" + k
+}
+
+// Options of send-keys command.
+type SendKeysOptions struct {
+	Literal bool // Send keys as literal UTF-8 characters instead of key names
+	Hex     bool // Keys are hexadecimal numbers of bytes to send
+	Repeat  int  // Number of times to send the keys, 0 sends them once
+}
+
+// Returns arguments of send-keys command.
+func (o SendKeysOptions) args(target string, keys []Key) ([]string, error) {
+	if o.Literal && o.Hex {
+		return nil, errors.New("Literal and Hex can't be set together")
+	}
+	if o.Repeat < 0 {
+		return nil, fmt.Errorf("Bad repeat count: %d", o.Repeat)
+	}
+
+	args := []string{"send-keys", "-t", target}
+	if o.Literal {
+		args = append(args, "-l")
+	}
+	if o.Hex {
+		args = append(args, "-H")
+	}
+	if o.Repeat != 0 {
+		args = append(args, "-N", strconv.Itoa(o.Repeat))
+	}
+	// Keys starting with "-" must not be parsed as flags
+	args = append(args, "--")
+	for _, k := range keys {
+		args = append(args, string(k))
+	}
+	return args, nil
+}
+
+// Sends the keys to the pane.
+func (p *Pane) SendKeys(opts SendKeysOptions, keys ...Key) error {
+	return p.SendKeysContext(context.Background(), opts, keys...)
+}
+
+// Same as SendKeys, but aborts the tmux command when ctx is done.
+func (p *Pane) SendKeysContext(ctx context.Context, opts SendKeysOptions, keys ...Key) error {
+	args, err := opts.args(p.target(), keys)
+	if err != nil {
+		return err
+	}
+	if _, _, err := p.server.run(ctx, args); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Types exactly the given text in the pane. Unlike SendKeys with Literal
+// option, tmux never interprets the text as key names or command separators:
+// printable characters are sent literally, and control characters, ";" and
+// invalid UTF-8 bytes are sent in hex mode. All of them are sent with a single
+// tmux command list.
+func (p *Pane) SendText(text string) error {
+	return p.SendTextContext(context.Background(), text)
+}
+
+// Same as SendText, but aborts the tmux command when ctx is done.
+func (p *Pane) SendTextContext(ctx context.Context, text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, _, err := p.server.run(ctx, sendTextArgs(p.target(), text)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Returns arguments of the list of send-keys commands separated by ";" that
+// types the text in the target pane.
+func sendTextArgs(target, text string) []string {
+	var args []string
+	var hex []Key      // Bytes of the current hex run
+	var literal []byte // Characters of the current literal run
+	flush := func() {
+		var opts SendKeysOptions
+		var keys []Key
+		switch {
+		case len(literal) > 0:
+			opts, keys = SendKeysOptions{Literal: true}, []Key{Key(literal)}
+		case len(hex) > 0:
+			opts, keys = SendKeysOptions{Hex: true}, hex
+		default:
+			return
+		}
+		if len(args) > 0 {
+			args = append(args, ";")
+		}
+		cmd, _ := opts.args(target, keys)
+		args = append(args, cmd...)
+		hex, literal = nil, nil
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		// An argument ending with ";" is parsed by tmux as a separator, so
+		// it is never sent literally
+		if (r == utf8.RuneError && size == 1) || unicode.IsControl(r) || r == ';' {
+			if len(literal) > 0 {
+				flush()
+			}
+			for j := 0; j < size; j++ {
+				hex = append(hex, Key(fmt.Sprintf("%02x", text[i+j])))
+			}
+		} else {
+			if len(hex) > 0 {
+				flush()
+			}
+			literal = append(literal, text[i:i+size]...)
+		}
+		i += size
+	}
+	flush()
+	return args
+}
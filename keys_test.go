@@ -0,0 +1,77 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendKeysOptionsArgs(t *testing.T) {
+	args, err := SendKeysOptions{Repeat: 3}.args("%1", []Key{Ctrl("c"), Meta(KeyLeft), KeyF5})
+	if err != nil {
+		t.Fatalf("args: %s", err)
+	}
+	expected := []string{"send-keys", "-t", "%1", "-N", "3", "--", "C-c", "M-Left", "F5"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Incorrect arguments (expected %v got %v)", expected, args)
+	}
+
+	if _, err := (SendKeysOptions{Literal: true, Hex: true}).args("%1", nil); err == nil {
+		t.Fatalf("Literal and Hex were accepted together")
+	}
+}
+
+func TestSendTextArgs(t *testing.T) {
+	args := sendTextArgs("%1", "ls -l; ü\x03\xff\r")
+	expected := []string{
+		"send-keys", "-t", "%1", "-l", "--", "ls -l", ";",
+		"send-keys", "-t", "%1", "-H", "--", "3b", ";",
+		"send-keys", "-t", "%1", "-l", "--", " ü", ";",
+		"send-keys", "-t", "%1", "-H", "--", "03", "ff", "0d",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Incorrect arguments (expected %v got %v)", expected, args)
+	}
+}
+
+func TestPaneSendText(t *testing.T) {
+	server, pane := createShellPane(t, "cat")
+	defer killServer(server)
+
+	// Key names, escaped separators and flags must be typed as is
+	text := `Enter C-c -l a\; ; ü`
+	for i := 1; i <= 2; i++ {
+		if i == 2 {
+			// Command lists are also sent through the control client
+			client, err := server.NewControlClient("")
+			if err != nil {
+				t.Fatalf("NewControlClient: %s", err)
+			}
+			defer client.Close()
+			server.Executor = client
+		}
+		if err := pane.RunCommand(text); err != nil {
+			t.Fatalf("RunCommand: %s", err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			out, err := pane.Capture()
+			if err != nil {
+				t.Fatalf("Capture: %s", err)
+			}
+			// Echoed input and the output of cat
+			if strings.Count(out, text+"\n") == 2*i {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Text was not typed exactly, got: %q", out)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Position of the main pane in MainPane layout.
+type Side int
+
+const (
+	SideLeft Side = iota
+	SideRight
+	SideTop
+	SideBottom
+)
+
+// Golden ratio used by Spiral layout.
+const phi = 1.618033988749895
+
+// Returns sizes of n cells filling total cells separated by one cell wide
+// borders. The remainder is given to the last cell.
+func evenSizes(total, n int) ([]int, error) {
+	avail := total - (n - 1)
+	if n <= 0 || avail < n {
+		return nil, fmt.Errorf("Can't fit %d panes into %d cells", n, total)
+	}
+	sizes := make([]int, n)
+	for i := range sizes {
+		sizes[i] = avail / n
+	}
+	sizes[n-1] += avail % n
+	return sizes, nil
+}
+
+// Returns a leaf cell.
+func leaf(x, y, w, h int) *Cell {
+	return &Cell{Type: CellPane, Width: w, Height: h, X: x, Y: y, PaneID: -1}
+}
+
+// Splits the area to cells with given sizes along the axis defined by typ.
+// Cells are created with the child function, which receives their geometry.
+// If there is a single cell, it is returned without a parent.
+func split(typ CellType, x, y, w, h int, sizes []int,
+	child func(i, x, y, w, h int) (*Cell, error),
+) (*Cell, error) {
+	if len(sizes) == 1 {
+		return child(0, x, y, w, h)
+	}
+
+	c := &Cell{Type: typ, Width: w, Height: h, X: x, Y: y, PaneID: -1}
+	for i, size := range sizes {
+		var ch *Cell
+		var err error
+		if typ == CellLeftRight {
+			ch, err = child(i, x, y, size, h)
+			x += size + 1
+		} else {
+			ch, err = child(i, x, y, w, size)
+			y += size + 1
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.Children = append(c.Children, ch)
+	}
+	return c, nil
+}
+
+// Returns a function creating leaf cells for split.
+func leaves() func(i, x, y, w, h int) (*Cell, error) {
+	return func(i, x, y, w, h int) (*Cell, error) {
+		return leaf(x, y, w, h), nil
+	}
+}
+
+// Checks arguments common for all generators.
+func checkSize(width, height, panes int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("Bad window size %dx%d", width, height)
+	}
+	if panes <= 0 {
+		return errors.New("Layout must contain at least one pane")
+	}
+	return nil
+}
+
+// Arranges panes in a grid with given number of columns. Rows are filled from
+// left to right, panes of the last incomplete row are stretched to the full
+// width.
+func Grid(width, height, panes, columns int) (*Cell, error) {
+	if err := checkSize(width, height, panes); err != nil {
+		return nil, err
+	}
+	if columns <= 0 {
+		return nil, errors.New("Grid must contain at least one column")
+	}
+	if columns > panes {
+		columns = panes
+	}
+
+	rows := (panes + columns - 1) / columns
+	heights, err := evenSizes(height, rows)
+	if err != nil {
+		return nil, err
+	}
+	return split(CellTopBottom, 0, 0, width, height, heights,
+		func(row, x, y, w, h int) (*Cell, error) {
+			n := columns
+			if row == rows-1 {
+				n = panes - columns*(rows-1)
+			}
+			widths, err := evenSizes(w, n)
+			if err != nil {
+				return nil, err
+			}
+			return split(CellLeftRight, x, y, w, h, widths, leaves())
+		})
+}
+
+// Arranges panes in a golden ratio spiral: each pane takes 1/phi of the
+// remaining area, going clockwise from the left top corner.
+func Spiral(width, height, panes int) (*Cell, error) {
+	if err := checkSize(width, height, panes); err != nil {
+		return nil, err
+	}
+	return spiral(0, 0, width, height, panes, 0)
+}
+
+func spiral(x, y, w, h, panes, i int) (*Cell, error) {
+	if panes == 1 {
+		return leaf(x, y, w, h), nil
+	}
+
+	typ, total := CellLeftRight, w
+	if i%2 == 1 {
+		typ, total = CellTopBottom, h
+	}
+	size := int(math.Round(float64(total-1) / phi))
+	rest := total - 1 - size
+	if size < 1 || rest < 1 {
+		return nil, fmt.Errorf("Can't fit %d panes into %dx%d cells", panes, w, h)
+	}
+
+	// The pane is placed first when moving right and down, and last when
+	// moving left and up.
+	paneFirst := i%4 < 2
+	sizes := []int{size, rest}
+	if !paneFirst {
+		sizes = []int{rest, size}
+	}
+	return split(typ, x, y, w, h, sizes, func(j, x, y, w, h int) (*Cell, error) {
+		if (j == 0) == paneFirst {
+			return leaf(x, y, w, h), nil
+		}
+		return spiral(x, y, w, h, panes-1, i+1)
+	})
+}
+
+// Arranges a main pane taking percent of the window width (or height for
+// SideTop and SideBottom) and other panes stacked evenly in a sidebar.
+func MainPane(width, height, panes, percent int, side Side) (*Cell, error) {
+	if err := checkSize(width, height, panes); err != nil {
+		return nil, err
+	}
+	if percent <= 0 || percent >= 100 {
+		return nil, fmt.Errorf("Bad main pane size: %d%%", percent)
+	}
+	if panes == 1 {
+		return leaf(0, 0, width, height), nil
+	}
+
+	typ, stack, total := CellLeftRight, CellTopBottom, width
+	if side == SideTop || side == SideBottom {
+		typ, stack, total = CellTopBottom, CellLeftRight, height
+	}
+	main := (total - 1) * percent / 100
+	rest := total - 1 - main
+	if main < 1 || rest < 1 {
+		return nil, fmt.Errorf("Can't fit main pane of %d%% into %d cells", percent, total)
+	}
+
+	mainFirst := side == SideLeft || side == SideTop
+	sizes := []int{main, rest}
+	if !mainFirst {
+		sizes = []int{rest, main}
+	}
+	return split(typ, 0, 0, width, height, sizes, func(i, x, y, w, h int) (*Cell, error) {
+		if (i == 0) == mainFirst {
+			return leaf(x, y, w, h), nil
+		}
+		stackTotal := h
+		if stack == CellLeftRight {
+			stackTotal = w
+		}
+		stackSizes, err := evenSizes(stackTotal, panes-1)
+		if err != nil {
+			return nil, err
+		}
+		return split(stack, x, y, w, h, stackSizes, leaves())
+	})
+}
+
+// Arranges panes in full width rows with given heights. Rows with zero height
+// share the remaining space evenly.
+func Rows(width, height int, heights []int) (*Cell, error) {
+	if err := checkSize(width, height, len(heights)); err != nil {
+		return nil, err
+	}
+
+	fixed, flexible := len(heights)-1, 0 // Borders are fixed too
+	for _, h := range heights {
+		if h < 0 {
+			return nil, fmt.Errorf("Bad row height: %d", h)
+		}
+		if h == 0 {
+			flexible++
+		}
+		fixed += h
+	}
+	rest := height - fixed
+	if rest < flexible || (flexible == 0 && rest != 0) {
+		return nil, fmt.Errorf("Rows don't fit the window height %d", height)
+	}
+
+	sizes := make([]int, len(heights))
+	copy(sizes, heights)
+	if flexible > 0 {
+		// Borders between flexible rows are already counted
+		flexSizes, _ := evenSizes(rest+flexible-1, flexible)
+		for i := range sizes {
+			if sizes[i] == 0 {
+				sizes[i], flexSizes = flexSizes[0], flexSizes[1:]
+			}
+		}
+	}
+	return split(CellTopBottom, 0, 0, width, height, sizes, leaves())
+}
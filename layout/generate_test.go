@@ -0,0 +1,89 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package layout
+
+import (
+	"testing"
+)
+
+// Checks that generated layout is valid and contains given number of panes.
+func checkGenerated(t *testing.T, name string, c *Cell, err error, width, height, panes int) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("%s: %s: %s", name, c, err)
+	}
+	if c.Width != width || c.Height != height {
+		t.Fatalf("%s: unexpected size %dx%d", name, c.Width, c.Height)
+	}
+	if len(c.Panes()) != panes {
+		t.Fatalf("%s: expected %d panes, got %d", name, panes, len(c.Panes()))
+	}
+	if _, err := Parse(c.String()); err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	for panes := 1; panes <= 7; panes++ {
+		c, err := Grid(159, 48, panes, 3)
+		checkGenerated(t, "Grid", c, err, 159, 48, panes)
+		c, err = Spiral(159, 48, panes)
+		checkGenerated(t, "Spiral", c, err, 159, 48, panes)
+		for _, side := range []Side{SideLeft, SideRight, SideTop, SideBottom} {
+			c, err = MainPane(159, 48, panes, 60, side)
+			checkGenerated(t, "MainPane", c, err, 159, 48, panes)
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	c, err := Grid(80, 24, 3, 2)
+	if err != nil {
+		t.Fatalf("Grid: %s", err)
+	}
+	if c.Body() != "80x24,0,0[80x11,0,0{39x11,0,0,40x11,40,0},80x12,0,12]" {
+		t.Fatalf("Unexpected layout: %s", c.Body())
+	}
+}
+
+func TestMainPane(t *testing.T) {
+	c, err := MainPane(101, 30, 3, 50, SideRight)
+	if err != nil {
+		t.Fatalf("MainPane: %s", err)
+	}
+	if c.Body() != "101x30,0,0{50x30,0,0[50x14,0,0,50x15,0,15],50x30,51,0}" {
+		t.Fatalf("Unexpected layout: %s", c.Body())
+	}
+}
+
+func TestRows(t *testing.T) {
+	c, err := Rows(80, 24, []int{3, 0, 5})
+	checkGenerated(t, "Rows", c, err, 80, 24, 3)
+	panes := c.Panes()
+	if panes[0].Height != 3 || panes[1].Height != 14 || panes[2].Height != 5 {
+		t.Fatalf("Unexpected layout: %s", c.Body())
+	}
+
+	if _, err := Rows(80, 24, []int{10, 20}); err == nil {
+		t.Fatalf("Rows that don't fit the window were accepted")
+	}
+	if _, err := Rows(80, 24, []int{10, 10}); err == nil {
+		t.Fatalf("Rows that don't fill the window were accepted")
+	}
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	if _, err := Grid(10, 2, 4, 1); err == nil {
+		t.Errorf("Grid: too many rows were accepted")
+	}
+	if _, err := Spiral(3, 3, 10); err == nil {
+		t.Errorf("Spiral: too many panes were accepted")
+	}
+	if _, err := MainPane(80, 24, 2, 100, SideLeft); err == nil {
+		t.Errorf("MainPane: bad percent was accepted")
+	}
+}
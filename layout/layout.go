@@ -0,0 +1,277 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+// Package layout parses and serializes tmux window layout strings, such as
+// "bb62,159x48,0,0{79x48,0,0,1,79x48,80,0,2}". These strings are printed by
+// the window_layout format variable and accepted by the select-layout command.
+// It also provides generators of layouts not available as tmux presets.
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type of the layout cell.
+type CellType int
+
+const (
+	CellPane      CellType = iota // Leaf cell containing a pane
+	CellLeftRight                 // Cell split to columns: {...}
+	CellTopBottom                 // Cell split to rows: [...]
+)
+
+// Represents a cell of the layout tree. Leaf cells contain panes, other cells
+// are split to children horizontally or vertically.
+type Cell struct {
+	Type     CellType
+	Width    int
+	Height   int
+	X        int     // Offset from the left edge of the window
+	Y        int     // Offset from the top edge of the window
+	PaneID   int     // Id of the pane for leaf cells or -1 if it is not set
+	Children []*Cell // Children of split cells
+}
+
+// Calculates the checksum of the layout description, the same way as tmux
+// does.
+func Checksum(s string) uint16 {
+	var csum uint16
+	for i := 0; i < len(s); i++ {
+		csum = (csum >> 1) + ((csum & 1) << 15)
+		csum += uint16(s[i])
+	}
+	return csum
+}
+
+// Parses the layout string. The checksum is verified.
+func Parse(s string) (*Cell, error) {
+	i := strings.IndexByte(s, ',')
+	if i != 4 {
+		return nil, errors.New("Layout must start with a checksum")
+	}
+	csum, err := strconv.ParseUint(s[:i], 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("Bad layout checksum: %s", err)
+	}
+	body := s[i+1:]
+	if uint16(csum) != Checksum(body) {
+		return nil, fmt.Errorf("Layout checksum mismatch: expected %04x", Checksum(body))
+	}
+	return ParseBody(body)
+}
+
+// Parses the layout description without the checksum.
+func ParseBody(body string) (*Cell, error) {
+	p := &parser{s: body}
+	c, err := p.cell()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected trailing characters")
+	}
+	return c, nil
+}
+
+// Recursive descent parser of layout descriptions.
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Bad layout at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) number() (int, error) {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, p.errorf("expected a number")
+	}
+	return strconv.Atoi(p.s[start:p.pos])
+}
+
+// Parses cell: WxH,X,Y followed by ",ID", "{cells}" or "[cells]".
+func (p *parser) cell() (*Cell, error) {
+	c := &Cell{Type: CellPane, PaneID: -1}
+	var err error
+	if c.Width, err = p.number(); err != nil {
+		return nil, err
+	}
+	if err = p.expect('x'); err != nil {
+		return nil, err
+	}
+	if c.Height, err = p.number(); err != nil {
+		return nil, err
+	}
+	if err = p.expect(','); err != nil {
+		return nil, err
+	}
+	if c.X, err = p.number(); err != nil {
+		return nil, err
+	}
+	if err = p.expect(','); err != nil {
+		return nil, err
+	}
+	if c.Y, err = p.number(); err != nil {
+		return nil, err
+	}
+
+	// Pane id is optional in leaf cells
+	if p.peek() == ',' && p.pos+1 < len(p.s) && p.s[p.pos+1] >= '0' && p.s[p.pos+1] <= '9' {
+		start := p.pos
+		p.pos++
+		id, _ := p.number()
+		// The number is a pane id only if it is not followed by 'x'
+		if p.peek() != 'x' {
+			c.PaneID = id
+			return c, nil
+		}
+		p.pos = start
+	}
+
+	var end byte
+	switch p.peek() {
+	case '{':
+		c.Type, end = CellLeftRight, '}'
+	case '[':
+		c.Type, end = CellTopBottom, ']'
+	default:
+		return c, nil
+	}
+	p.pos++
+	for {
+		child, err := p.cell()
+		if err != nil {
+			return nil, err
+		}
+		c.Children = append(c.Children, child)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(end); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Returns the layout description without the checksum.
+func (c *Cell) Body() string {
+	var b strings.Builder
+	c.write(&b)
+	return b.String()
+}
+
+func (c *Cell) write(b *strings.Builder) {
+	fmt.Fprintf(b, "%dx%d,%d,%d", c.Width, c.Height, c.X, c.Y)
+	var start, end byte
+	switch c.Type {
+	case CellPane:
+		if c.PaneID >= 0 {
+			fmt.Fprintf(b, ",%d", c.PaneID)
+		}
+		return
+	case CellLeftRight:
+		start, end = '{', '}'
+	case CellTopBottom:
+		start, end = '[', ']'
+	}
+	b.WriteByte(start)
+	for i, child := range c.Children {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		child.write(b)
+	}
+	b.WriteByte(end)
+}
+
+// Returns the layout string with the recomputed checksum, which can be passed
+// to select-layout.
+func (c *Cell) String() string {
+	body := c.Body()
+	return fmt.Sprintf("%04x,%s", Checksum(body), body)
+}
+
+// Returns leaf cells in the order tmux assigns panes to them.
+func (c *Cell) Panes() []*Cell {
+	if c.Type == CellPane {
+		return []*Cell{c}
+	}
+	panes := []*Cell{}
+	for _, child := range c.Children {
+		panes = append(panes, child.Panes()...)
+	}
+	return panes
+}
+
+// Checks that children of split cells fill their parents, taking into account
+// one cell wide borders between them.
+func (c *Cell) Validate() error {
+	if c.Width <= 0 || c.Height <= 0 {
+		return fmt.Errorf("Cell %dx%d,%d,%d has empty size", c.Width, c.Height, c.X, c.Y)
+	}
+	if c.Type == CellPane {
+		if len(c.Children) != 0 {
+			return errors.New("Pane cell can't contain children")
+		}
+		return nil
+	}
+	if len(c.Children) == 0 {
+		return fmt.Errorf("Split cell %dx%d,%d,%d doesn't contain children", c.Width, c.Height, c.X, c.Y)
+	}
+
+	x, y := c.X, c.Y
+	for _, child := range c.Children {
+		if child.X != x || child.Y != y {
+			return fmt.Errorf("Cell %dx%d,%d,%d has bad offset (expected %d,%d)",
+				child.Width, child.Height, child.X, child.Y, x, y)
+		}
+		if c.Type == CellLeftRight {
+			if child.Height != c.Height {
+				return fmt.Errorf("Cell %dx%d,%d,%d must have height %d",
+					child.Width, child.Height, child.X, child.Y, c.Height)
+			}
+			x += child.Width + 1
+		} else {
+			if child.Width != c.Width {
+				return fmt.Errorf("Cell %dx%d,%d,%d must have width %d",
+					child.Width, child.Height, child.X, child.Y, c.Width)
+			}
+			y += child.Height + 1
+		}
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Type == CellLeftRight && x-1 != c.X+c.Width {
+		return fmt.Errorf("Children of cell %dx%d,%d,%d don't fill its width", c.Width, c.Height, c.X, c.Y)
+	}
+	if c.Type == CellTopBottom && y-1 != c.Y+c.Height {
+		return fmt.Errorf("Children of cell %dx%d,%d,%d don't fill its height", c.Width, c.Height, c.X, c.Y)
+	}
+	return nil
+}
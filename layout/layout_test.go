@@ -0,0 +1,94 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package layout
+
+import (
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	layouts := []string{
+		"bb62,159x48,0,0{79x48,0,0,79x48,80,0}",
+		"7ad9,100x30,0,0[100x15,0,0{50x15,0,0,0,49x15,51,0,397},100x14,0,16,396]",
+		"b25f,80x24,0,0,2",
+	}
+	for _, s := range layouts {
+		c, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", s, err)
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate(%q): %s", s, err)
+		}
+		if c.String() != s {
+			t.Errorf("Expected %q, got %q", s, c.String())
+		}
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	c, err := Parse("7ad9,100x30,0,0[100x15,0,0{50x15,0,0,0,49x15,51,0,397},100x14,0,16,396]")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if c.Type != CellTopBottom || len(c.Children) != 2 {
+		t.Fatalf("Unexpected root cell: %+v", c)
+	}
+	if c.Children[0].Type != CellLeftRight {
+		t.Fatalf("Unexpected first row: %+v", c.Children[0])
+	}
+
+	panes := c.Panes()
+	ids := []int{0, 397, 396}
+	if len(panes) != len(ids) {
+		t.Fatalf("Expected %d panes, got %d", len(ids), len(panes))
+	}
+	for i, p := range panes {
+		if p.PaneID != ids[i] {
+			t.Errorf("Pane %d: expected id %d got %d", i, ids[i], p.PaneID)
+		}
+	}
+	if panes[1].Width != 49 || panes[1].X != 51 {
+		t.Errorf("Unexpected pane geometry: %+v", panes[1])
+	}
+}
+
+func TestParseWithoutPaneIds(t *testing.T) {
+	c, err := ParseBody("159x48,0,0{79x48,0,0,79x48,80,0}")
+	if err != nil {
+		t.Fatalf("ParseBody: %s", err)
+	}
+	if len(c.Panes()) != 2 || c.Panes()[1].PaneID != -1 {
+		t.Fatalf("Unexpected panes: %+v", c.Panes())
+	}
+	if c.Body() != "159x48,0,0{79x48,0,0,79x48,80,0}" {
+		t.Fatalf("Unexpected body: %s", c.Body())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	bad := []string{
+		"",
+		"0000,159x48,0,0{79x48,0,0,79x48,80,0}", // Bad checksum
+		"bb62",
+		"159x48,0,0,1",
+	}
+	for _, s := range bad {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected an error", s)
+		}
+	}
+	for _, s := range []string{"80x24,0,0{", "80x24,0,0[80x24,0,0,1", "80x24", "80x24,0,0,1}"} {
+		if _, err := ParseBody(s); err == nil {
+			t.Errorf("ParseBody(%q): expected an error", s)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c, _ := ParseBody("159x48,0,0{79x48,0,0,1,78x48,80,0,2}")
+	if err := c.Validate(); err == nil {
+		t.Fatalf("Layout with a gap was accepted")
+	}
+}
@@ -0,0 +1,220 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Streaming of pane output using the control mode.
+
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// Output of the pane is paused when the reader falls behind by this many
+	// bytes, and continued after it reads the buffered output.
+	outputPauseBytes = 1 << 20
+	// tmux pauses the pane when its output is buffered for this many seconds
+	// on the server side.
+	outputPauseAfter = 5
+)
+
+// Chunk of the pane output.
+type outputChunk struct {
+	data []byte
+	time time.Time // When tmux received the output from the pane
+}
+
+// Buffers output of a single pane between control client and the reader.
+type outputStream struct {
+	client    *ControlClient
+	closeOnce sync.Once // Closes the client once, Close is not reentrant
+	target    string    // Pane target, e.g. "%1"
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	chunks []outputChunk
+	size   int   // Total size of buffered chunks
+	paused bool  // Whether the pane was paused by tmux or by us
+	closed bool  // Whether the client has exited or ctx is done
+	err    error // Returned after the buffered output when the stream is closed
+}
+
+// Returns a reader that receives everything the pane prints starting from
+// this moment. Output is received through a separate control mode client,
+// which is closed when ctx is done or the reader is closed; after that Read
+// returns ctx.Err() or io.ErrClosedPipe. The reader must be closed to release
+// the client if ctx is never done.
+//
+// If the reader falls too far behind, output of the pane is paused, so the
+// tmux server does not buffer it indefinitely. The output printed while the
+// pane is paused is lost.
+func (p *Pane) Output(ctx context.Context) (io.ReadCloser, error) {
+	o, err := p.openOutput(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			chunks, err := o.next()
+			for _, c := range chunks {
+				if _, err := pw.Write(c.data); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return &outputReader{PipeReader: pr, stream: o}, nil
+}
+
+// Reader of the pane output returned by Pane.Output.
+type outputReader struct {
+	*io.PipeReader
+	stream *outputStream
+}
+
+// Closes the reader and the control client receiving the output.
+func (r *outputReader) Close() error {
+	err := r.PipeReader.Close()
+	r.stream.closeClient()
+	return err
+}
+
+// Starts receiving output of the pane with a new control client.
+func (p *Pane) openOutput(ctx context.Context) (*outputStream, error) {
+	target := p.target()
+	client, err := p.server.NewControlClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enable flow control, so tmux pauses the pane instead of buffering its
+	// output forever. Not supported by tmux older than 3.2, so errors are
+	// ignored.
+	client.Run(ctx, []string{"refresh-client", "-f",
+		fmt.Sprintf("pause-after=%d", outputPauseAfter)})
+
+	events, cancel := client.SubscribeFilter(func(e Event) bool {
+		switch e := e.(type) {
+		case OutputEvent:
+			return e.PaneId == p.ID
+		case PauseEvent:
+			return e.PaneId == p.ID
+		}
+		return false
+	})
+	o := &outputStream{client: client, target: target}
+	o.cond = sync.NewCond(&o.mu)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			o.closeWithError(ctx.Err())
+			o.closeClient()
+		case <-client.Done():
+		}
+	}()
+	go func() {
+		o.readEvents(events)
+		cancel()
+		o.closeClient()
+	}()
+
+	return o, nil
+}
+
+// Receives output of the pane from control client notifications until the
+// client exits.
+func (o *outputStream) readEvents(events <-chan Event) {
+	defer o.closeWithError(io.EOF)
+
+	for e := range events {
+		switch e := e.(type) {
+		case OutputEvent:
+			// Age is the time the output spent in the buffer of tmux
+			t := time.Now().Add(-time.Duration(e.Age) * time.Millisecond)
+			o.mu.Lock()
+			o.chunks = append(o.chunks, outputChunk{data: e.Data, time: t})
+			o.size += len(e.Data)
+			pause := o.size > outputPauseBytes && !o.paused
+			if pause {
+				o.paused = true
+			}
+			o.cond.Signal()
+			o.mu.Unlock()
+			if pause {
+				o.client.Run(context.Background(), []string{"refresh-client", "-A", o.target + ":pause"})
+			}
+		case PauseEvent:
+			o.mu.Lock()
+			o.paused = true
+			o.cond.Signal()
+			o.mu.Unlock()
+		}
+	}
+}
+
+// Waits for the output and returns the buffered chunks. After the stream is
+// closed and all chunks are returned, returns the reason of closing. Continues
+// the paused pane once the buffer is drained.
+func (o *outputStream) next() ([]outputChunk, error) {
+	for {
+		o.mu.Lock()
+		for len(o.chunks) == 0 && !o.closed && !o.paused {
+			o.cond.Wait()
+		}
+		chunks, closed, err := o.chunks, o.closed, o.err
+		resume := o.paused && len(chunks) == 0
+		o.chunks, o.size = nil, 0
+		if resume {
+			o.paused = false
+		}
+		o.mu.Unlock()
+
+		if len(chunks) > 0 {
+			return chunks, nil
+		}
+		if closed {
+			return nil, err
+		}
+		if resume {
+			// Errors are ignored: if the client has exited, the stream will
+			// be closed soon.
+			go o.client.Run(context.Background(), []string{"refresh-client", "-A", o.target + ":continue"})
+		}
+	}
+}
+
+// Waits until tmux delivers the output printed so far and closes the client.
+// The stream is closed after the delivered output is read.
+func (o *outputStream) drain() {
+	// Notifications sent before the reply to the command are read before it
+	o.client.Run(context.Background(), []string{"display-message", "-p", ""})
+	o.closeClient()
+}
+
+// Closes the control client and waits for it to exit.
+func (o *outputStream) closeClient() {
+	o.closeOnce.Do(func() { o.client.Close() })
+}
+
+// Wakes up the reader, which receives the error after the buffered output.
+// Only the first error is kept.
+func (o *outputStream) closeWithError(err error) {
+	o.mu.Lock()
+	if !o.closed {
+		o.closed, o.err = true, err
+	}
+	o.cond.Signal()
+	o.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestPaneOutput(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	panes, err := session.ListPanes()
+	if err != nil || len(panes) == 0 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	pane := panes[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r, err := pane.Output(ctx)
+	if err != nil {
+		t.Fatalf("Output: %s", err)
+	}
+
+	if err := pane.RunCommand("echo output-\\\\test"); err != nil {
+		t.Fatalf("RunCommand: %s", err)
+	}
+
+	var out bytes.Buffer
+	buf := make([]byte, 1024)
+	for !bytes.Contains(out.Bytes(), []byte("output-\\test\r\n")) {
+		n, err := r.Read(buf)
+		out.Write(buf[:n])
+		if err == io.EOF || err == context.DeadlineExceeded {
+			t.Fatalf("Output was not received: %q", out.String())
+		}
+	}
+
+	cancel()
+	if _, err := io.Copy(ioutil.Discard, r); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+	r.Close()
+
+	// Closing the reader detaches its client
+	r, err = pane.Output(context.Background())
+	if err != nil {
+		t.Fatalf("Output: %s", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	if _, err := r.Read(buf); err != io.ErrClosedPipe {
+		t.Fatalf("Expected %v, got %v", io.ErrClosedPipe, err)
+	}
+	clients, err := server.ListClients()
+	if err != nil {
+		t.Fatalf("ListClients: %s", err)
+	}
+	if len(clients) != 0 {
+		t.Fatalf("Client of the output was not detached: %+v", clients)
+	}
+}
@@ -4,22 +4,38 @@
 package tmux
 
 import (
+	"context"
 	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
 )
 
 // Represent a tmux pane:
 // https://github.com/tmux/tmux/wiki/Getting-Started#sessions-windows-and-panes
 type Pane struct {
-	ID          int
-	SessionId   int
-	SessionName string
-	WindowId    int
-	WindowName  string
-	WindowIndex int
-	Active      bool
+	ID          int    `tmux:"pane_id"`
+	SessionId   int    `tmux:"session_id"`
+	SessionName string `tmux:"session_name"`
+	WindowId    int    `tmux:"window_id"`
+	WindowName  string `tmux:"window_name"`
+	WindowIndex int    `tmux:"window_index"`
+	Active      bool   `tmux:"pane_active"`
+
+	Pid            int    `tmux:"pane_pid"`             // PID of the first process in the pane
+	Tty            string `tmux:"pane_tty"`             // Pseudo terminal of the pane
+	Width          int    `tmux:"pane_width"`           // Width of the pane in cells
+	Height         int    `tmux:"pane_height"`          // Height of the pane in cells
+	Left           int    `tmux:"pane_left"`            // Left column of the pane in the window
+	Top            int    `tmux:"pane_top"`             // Top row of the pane in the window
+	CurrentCommand string `tmux:"pane_current_command"` // Current command if available
+	CurrentPath    string `tmux:"pane_current_path"`    // Current path if available
+	Title          string `tmux:"pane_title"`           // Title of the pane
+	Dead           bool   `tmux:"pane_dead"`            // True if the process in the pane has exited
+	DeadStatus     int    `tmux:"pane_dead_status"`     // Exit status of the process in the dead pane
+	InMode         bool   `tmux:"pane_in_mode"`         // True if the pane is in a mode (copy mode etc)
+	CursorX        int    `tmux:"cursor_x"`             // Cursor X position in the pane
+	CursorY        int    `tmux:"cursor_y"`             // Cursor Y position in the pane
+	HistorySize    int    `tmux:"history_size"`         // Number of lines in the pane history
+
+	server *Server
 }
 
 // Creates a new pane object.
@@ -45,75 +61,56 @@ func NewPane(id int, sessionId int, sessionName string, windowId int,
 //   - `-s`: target is a session. If neither is given, target is a window (or
 //     the current window).
 func ListPanes(args []string) ([]Pane, error) {
-	format := strings.Join([]string{
-		"#{session_id}",
-		"#{session_name}",
-		"#{window_id}",
-		"#{window_name}",
-		"#{window_index}",
-		"#{pane_id}",
-		"#{pane_active}",
-	}, ":")
-
-	args = append([]string{"list-panes", "-F", format}, args...)
-
-	out, _, err := RunCmd(args)
-	if err != nil {
-		return nil, err
-	}
+	return ListPanesContext(context.Background(), args)
+}
 
-	outLines := strings.Split(out, "\n")
+// Same as ListPanes, but aborts the tmux command when ctx is done.
+func ListPanesContext(ctx context.Context, args []string) ([]Pane, error) {
+	var s *Server
+	return s.listPanes(ctx, args)
+}
+
+// Returns a list of panes managed by this server. See ListPanes.
+func (s *Server) listPanes(ctx context.Context, args []string) ([]Pane, error) {
 	panes := []Pane{}
-	re := regexp.MustCompile(`\$([0-9]+):(.+):@([0-9]+):(.+):([0-9]+):%([0-9]+):([01])`)
-	const paneParts = 7
-
-	for _, line := range outLines {
-		result := re.FindStringSubmatch(line)
-		if len(result) <= paneParts {
-			continue
-		}
-
-		sessionID, errAtoi := strconv.Atoi(result[1])
-		if errAtoi != nil {
-			return nil, errAtoi
-		}
-
-		windowID, errAtoi := strconv.Atoi(result[3])
-		if errAtoi != nil {
-			return nil, errAtoi
-		}
-
-		windowIndex, errAtoi := strconv.Atoi(result[5])
-		if errAtoi != nil {
-			return nil, errAtoi
-		}
-
-		paneIndex, errAtoi := strconv.Atoi(result[6])
-		if errAtoi != nil {
-			return nil, errAtoi
-		}
-
-		panes = append(panes, Pane{
-			ID:          paneIndex,
-			SessionId:   sessionID,
-			SessionName: result[2],
-			WindowId:    windowID,
-			WindowName:  result[4],
-			WindowIndex: windowIndex,
-			Active:      result[7] == "1",
-		})
+	args = append([]string{"list-panes"}, args...)
+	if err := s.QueryList(ctx, &panes, args...); err != nil {
+		return nil, err
+	}
+	for i := range panes {
+		panes[i].server = s
 	}
 
 	return panes, nil
 }
 
+// Re-reads the state of this pane from tmux.
+func (p *Pane) Refresh() error {
+	return p.RefreshContext(context.Background())
+}
+
+// Same as Refresh, but aborts the tmux command when ctx is done.
+func (p *Pane) RefreshContext(ctx context.Context) error {
+	return p.server.QueryDisplay(ctx, p.target(), p)
+}
+
+// Returns target of this pane for tmux commands.
+func (p *Pane) target() string {
+	return fmt.Sprintf("%%%d", p.ID)
+}
+
 // Returns current path for this pane.
 func (p *Pane) GetCurrentPath() (string, error) {
+	return p.GetCurrentPathContext(context.Background())
+}
+
+// Same as GetCurrentPath, but aborts the tmux command when ctx is done.
+func (p *Pane) GetCurrentPathContext(ctx context.Context) (string, error) {
 	args := []string{
 		"display-message",
 		"-P", "-F", "#{pane_current_path}",
 	}
-	out, _, err := RunCmd(args)
+	out, _, err := p.server.run(ctx, args)
 	if err != nil {
 		return "", err
 	}
@@ -124,15 +121,14 @@ func (p *Pane) GetCurrentPath() (string, error) {
 	return out, nil
 }
 
+// Captures the visible content of the pane.
 func (p *Pane) Capture() (string, error) {
-	args := []string{
-		"capture-pane",
-		"-t",
-		fmt.Sprintf("%%%d", p.ID),
-		"-p",
-	}
+	return p.CaptureContext(context.Background())
+}
 
-	out, stdErr, err := RunCmd(args)
+// Same as Capture, but aborts the tmux command when ctx is done.
+func (p *Pane) CaptureContext(ctx context.Context) (string, error) {
+	out, stdErr, err := p.server.run(ctx, CaptureOptions{}.args(p.target()))
 	if err != nil {
 		return stdErr, err
 	}
@@ -143,32 +139,36 @@ func (p *Pane) Capture() (string, error) {
 	return out, nil
 }
 
-// RunCommand runs a command in the pane.
+// RunCommand runs a command in the pane. The command is typed exactly as
+// given with SendText and followed by Enter.
 func (p *Pane) RunCommand(command string) error {
-	args := []string{
-		"send-keys",
-		"-t",
-		fmt.Sprintf("%%%d", p.ID),
-		command,
-		"C-m",
-	}
-	_, stdErr, err := RunCmd(args)
-	if err != nil {
-		return fmt.Errorf("%v: %s", err, stdErr)
+	return p.RunCommandContext(context.Background(), command)
+}
+
+// Same as RunCommand, but aborts the tmux command when ctx is done.
+func (p *Pane) RunCommandContext(ctx context.Context, command string) error {
+	// Enter is sent in the same command list, so the command is never typed
+	// without it
+	if _, _, err := p.server.run(ctx, sendTextArgs(p.target(), command+"\r")); err != nil {
+		return err
 	}
 	return nil
 }
 
 // Selects the pane.
 func (p *Pane) Select() error {
+	return p.SelectContext(context.Background())
+}
+
+// Same as Select, but aborts the tmux command when ctx is done.
+func (p *Pane) SelectContext(ctx context.Context) error {
 	args := []string{
 		"select-pane",
 		"-t",
-		fmt.Sprintf("%%%d", p.ID),
+		p.target(),
 	}
-	_, stdErr, err := RunCmd(args)
-	if err != nil {
-		return fmt.Errorf("%v: %s", err, stdErr)
+	if _, _, err := p.server.run(ctx, args); err != nil {
+		return err
 	}
 	return nil
 }
@@ -35,3 +35,27 @@ func TestPaneGetCurrentPath(t *testing.T) {
 		t.Errorf("%s", err)
 	}
 }
+
+func TestPaneRefresh(t *testing.T) {
+	s := createSession()
+	defer sessionsReaper(s.Name)
+
+	panes, err := s.ListPanes()
+	if err != nil || len(panes) == 0 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	pane := panes[0]
+	if pane.Pid == 0 || pane.Tty == "" || pane.Width == 0 || pane.Height == 0 {
+		t.Fatalf("Pane state was not populated: %+v", pane)
+	}
+
+	if _, _, err := RunCmd([]string{"select-pane", "-t", pane.target(), "-T", "test-title"}); err != nil {
+		t.Fatalf("select-pane: %s", err)
+	}
+	if err := pane.Refresh(); err != nil {
+		t.Fatalf("Refresh: %s", err)
+	}
+	if pane.Title != "test-title" {
+		t.Fatalf("Incorrect title (expected %s got %s)", "test-title", pane.Title)
+	}
+}
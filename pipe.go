@@ -0,0 +1,247 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Piping of pane input and output with pipe-pane command.
+
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Maximum number of bytes copied to the rotated log file at once. tmux writes
+// to the pipe as the pane prints, so reads of busy panes are larger and the
+// log command starts fewer processes.
+const logBlockSize = 64 * 1024
+
+// Options of pipe-pane command.
+type PipeOptions struct {
+	Input   bool      // Send output of the command to the pane as input
+	Output  bool      // Send output of the pane to the command, default if Input is not set
+	Toggle  bool      // Open the pipe only if the pane is not piped yet, close it otherwise
+	Command string    // Shell command to pipe the pane with
+	Writer  io.Writer // Receives output of the pane instead of Command
+
+	// Log file that receives output of the pane instead of Command. Unlike
+	// Writer, it is written by a shell started by tmux, so logging continues
+	// after this process exits.
+	LogFile string
+	// Size of the log file in bytes, at which it is rotated. Rotated files
+	// are renamed to LogFile.1, LogFile.2 and so on. Zero disables rotation.
+	LogMaxSize int64
+	// Number of rotated log files to keep. With zero the log file is removed
+	// on rotation.
+	LogKeep int
+}
+
+// Returns a shell command appending its input to the log file and rotating
+// it. Input is copied with dd, which reads the pipe once per block, so the file
+// never grows over the limit and no input is lost. Each read starts dd and wc
+// processes, so without rotation the input is copied with a single cat.
+func (o PipeOptions) logCommand() string {
+	f := shellQuote(o.LogFile)
+	if o.LogMaxSize <= 0 {
+		return "cat >> " + f
+	}
+
+	rotate := "rm -f " + f
+	if o.LogKeep > 0 {
+		rotate = fmt.Sprintf("i=%d; while [ $i -gt 1 ]; do "+
+			"mv -f %s.$((i-1)) %s.$i 2>/dev/null; i=$((i-1)); done; mv -f %s %s.1",
+			o.LogKeep, f, f, f, f)
+	}
+	// Exits when dd copies nothing, which means the pipe was closed
+	return fmt.Sprintf("size=$(wc -c 2>/dev/null < %s || echo 0); while :; do "+
+		"if [ $size -ge %d ]; then %s; size=0; fi; "+
+		"n=$((%d - size)); if [ $n -gt %d ]; then n=%d; fi; "+
+		"dd bs=$n count=1 2>/dev/null >> %s || exit 1; "+
+		"copied=$(wc -c < %s); [ $copied -gt $size ] || exit 0; size=$copied; "+
+		"done", f, o.LogMaxSize, rotate, o.LogMaxSize, logBlockSize, logBlockSize, f, f)
+}
+
+// Returns arguments of pipe-pane command.
+func (o PipeOptions) args(target string, command string) []string {
+	args := []string{"pipe-pane", "-t", target}
+	if o.Input {
+		args = append(args, "-I")
+	}
+	if o.Output {
+		args = append(args, "-O")
+	}
+	if o.Toggle {
+		args = append(args, "-o")
+	}
+	return append(args, command)
+}
+
+// Represents the pipe opened with Pane.Pipe.
+type Pipe struct {
+	done chan struct{}
+	err  error
+}
+
+// Waits until the pipe is closed and returns an error of writing the output
+// to PipeOptions.Writer. For pipes to commands returns immediately.
+func (p *Pipe) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// Pipes the pane to the shell command, the log file or the writer. If
+// PipeOptions.Writer is set, the output is delivered to it through a temporary
+// FIFO until the pipe is closed with StopPipe, the pane exits or this process
+// exits. Waits until the pipe starts, so no output is lost after Pipe returns.
+// Any previous pipe of the pane is
+// closed. With Toggle option only the previous pipe is closed if it exists, and
+// the returned pipe is already closed.
+func (p *Pane) Pipe(opts PipeOptions) (*Pipe, error) {
+	return p.PipeContext(context.Background(), opts)
+}
+
+// Same as Pipe, but aborts the tmux command when ctx is done.
+func (p *Pane) PipeContext(ctx context.Context, opts PipeOptions) (*Pipe, error) {
+	pipe := &Pipe{done: make(chan struct{})}
+	command := opts.Command
+	if opts.LogFile != "" {
+		if opts.Command != "" || opts.Writer != nil || opts.Input {
+			return nil, errors.New("LogFile can only receive output of the pane")
+		}
+		if opts.LogMaxSize < 0 || opts.LogKeep < 0 {
+			return nil, errors.New("Bad log file limits")
+		}
+		command = opts.logCommand()
+	}
+
+	if opts.Writer == nil {
+		if command == "" {
+			return nil, errors.New("Command, LogFile or Writer must be set")
+		}
+		if _, _, err := p.server.run(ctx, opts.args(p.target(), command)); err != nil {
+			return nil, err
+		}
+		close(pipe.done)
+		return pipe, nil
+	}
+
+	if opts.Command != "" || opts.Input {
+		return nil, errors.New("Writer can only receive output of the pane")
+	}
+	dir, err := ioutil.TempDir("", "go-tmux-pipe")
+	if err != nil {
+		return nil, err
+	}
+	fifo := filepath.Join(dir, "output")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	opened := make(chan struct{})
+	go func() {
+		defer close(pipe.done)
+		defer os.RemoveAll(dir)
+		// Blocks until the command opens the FIFO for writing
+		f, err := os.Open(fifo)
+		close(opened)
+		if err != nil {
+			pipe.err = err
+			return
+		}
+		defer f.Close()
+		if _, err := io.Copy(opts.Writer, f); err != nil {
+			pipe.err = err
+		}
+	}()
+
+	_, _, err = p.server.run(ctx, opts.args(p.target(), "cat > "+shellQuote(fifo)))
+	piped := false
+	if err == nil {
+		piped, err = p.waitPipeOpened(ctx, opened)
+	}
+	if !piped {
+		// Unblock the reader, so it removes the FIFO
+		releaseFifo(fifo, pipe.done)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !piped && !opts.Toggle {
+		return nil, errors.New("Pipe was closed before the command started")
+	}
+
+	// With Toggle option the previous pipe may be closed instead of opening
+	// ours, then the returned pipe is already closed
+	return pipe, nil
+}
+
+// Waits until the pipe command opens the FIFO. Returns false if the pane is
+// not piped anymore, e.g. because the command or the pane has exited.
+func (p *Pane) waitPipeOpened(ctx context.Context, opened <-chan struct{}) (bool, error) {
+	for {
+		select {
+		case <-opened:
+			return true, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		var state struct {
+			Piped bool `tmux:"pane_pipe"`
+		}
+		if err := p.server.QueryDisplay(ctx, p.target(), &state); err != nil {
+			return false, err
+		}
+		if !state.Piped {
+			select {
+			case <-opened:
+				// The command has already written everything and exited
+				return true, nil
+			default:
+				return false, nil
+			}
+		}
+	}
+}
+
+// Opens the write end of the FIFO to unblock the reader waiting for the pipe
+// command, and waits until the reader exits.
+func releaseFifo(fifo string, done <-chan struct{}) {
+	for {
+		// Opening without a reader fails, so retry until the reader starts
+		// waiting or exits
+		f, err := os.OpenFile(fifo, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if err == nil {
+			f.Close()
+			<-done
+			return
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Closes the pipe of the pane.
+func (p *Pane) StopPipe() error {
+	return p.StopPipeContext(context.Background())
+}
+
+// Same as StopPipe, but aborts the tmux command when ctx is done.
+func (p *Pane) StopPipeContext(ctx context.Context) error {
+	args := []string{"pipe-pane", "-t", p.target()}
+	if _, _, err := p.server.run(ctx, args); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestPipeOptionsArgs(t *testing.T) {
+	args := PipeOptions{Input: true, Output: true, Toggle: true}.args("%1", "cat")
+	expected := []string{"pipe-pane", "-t", "%1", "-I", "-O", "-o", "cat"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Incorrect arguments (expected %v got %v)", expected, args)
+	}
+}
+
+func TestPanePipe(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var out syncBuffer
+	pipe, err := pane.Pipe(PipeOptions{Writer: &out})
+	if err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	if _, err := pane.Exec(ctx, "echo piped-output"); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+
+	// Toggling closes the pipe
+	toggled, err := pane.Pipe(PipeOptions{Writer: ioutil.Discard, Toggle: true})
+	if err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	if err := toggled.Wait(); err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if err := pipe.Wait(); err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if !strings.Contains(out.String(), "piped-output\r\n") {
+		t.Fatalf("Output was not piped: %q", out.String())
+	}
+
+	// Pipe to a command
+	dir, err := ioutil.TempDir("", "go-tmux-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	log := filepath.Join(dir, "pane.log")
+	if _, err := pane.Pipe(PipeOptions{Command: "cat >> " + shellQuote(log)}); err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	if _, err := pane.Exec(ctx, "echo logged-output"); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+	if err := pane.StopPipe(); err != nil {
+		t.Fatalf("StopPipe: %s", err)
+	}
+	for {
+		data, _ := ioutil.ReadFile(log)
+		if strings.Contains(string(data), "logged-output\r\n") {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("Output was not logged: %q", data)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestPanePipeNotOpened(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The pane is not piped, so the FIFO will never be opened
+	piped, err := pane.waitPipeOpened(ctx, make(chan struct{}))
+	if err != nil || piped {
+		t.Fatalf("waitPipeOpened: %v, %s", piped, err)
+	}
+
+	dir, err := ioutil.TempDir("", "go-tmux-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	fifo := filepath.Join(dir, "output")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Fatalf("Mkfifo: %s", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if f, err := os.Open(fifo); err == nil {
+			f.Close()
+		}
+	}()
+	released := make(chan struct{})
+	go func() {
+		releaseFifo(fifo, done)
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-ctx.Done():
+		t.Fatalf("Reader of the FIFO was not released")
+	}
+}
+
+func TestPipeLogCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-tmux-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	log := filepath.Join(dir, "pane's.log")
+
+	input := strings.Repeat("0123456789", 10)
+	cmd := exec.Command("sh", "-c", PipeOptions{LogFile: log, LogMaxSize: 40, LogKeep: 1}.logCommand())
+	cmd.Stdin = strings.NewReader(input)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Log command: %s: %s", err, out)
+	}
+
+	expected := map[string]string{
+		log:        input[80:],
+		log + ".1": input[40:80],
+		log + ".2": "",
+	}
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(name)
+		if content == "" {
+			if !os.IsNotExist(err) {
+				t.Errorf("File %s was not removed", name)
+			}
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("Incorrect content of %s (expected %q got %q)", name, content, data)
+		}
+	}
+}
+
+func TestPanePipeLogFile(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "go-tmux-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	log := filepath.Join(dir, "pane.log")
+	if _, err := pane.Pipe(PipeOptions{LogFile: log, LogMaxSize: 64, LogKeep: 2}); err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	if _, err := pane.Exec(ctx, "printf '%s\\n' $(seq 100 150)"); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+
+	// Log is written by the shell, wait until the output appears in it
+	for {
+		data, _ := ioutil.ReadFile(log)
+		rotated, _ := ioutil.ReadFile(log + ".1")
+		if len(rotated) == 64 && len(data) <= 64 && strings.Contains(string(rotated)+string(data), "150\r\n") {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("Output was not logged: %q, %q", rotated, data)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := pane.StopPipe(); err != nil {
+		t.Fatalf("StopPipe: %s", err)
+	}
+}
@@ -0,0 +1,218 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Queries of tmux objects state using format variables:
+// https://man7.org/linux/man-pages/man1/tmux.1.html#FORMATS
+
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Separator of values in format strings. tmux escapes control characters in
+// names of objects, so it never appears inside of values. tmux also replaces
+// it in the output when the client does not support UTF-8, so commands must be
+// run with -u flag, as DefaultExecutor and ControlClient do.
+const formatSeparator = "\x1f"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Struct field filled from tmux format variable.
+type formatField struct {
+	index []int  // Index sequence for reflect.Value.FieldByIndex
+	name  string // Name of the format variable
+}
+
+// Returns fields of the struct type that are tagged with `tmux:"variable"`.
+// Fields of embedded structs are included.
+func formatFields(t reflect.Type) []formatField {
+	fields := []formatField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("tmux")
+		switch {
+		case tag == "-":
+			continue
+		case tag != "":
+			fields = append(fields, formatField{index: []int{i}, name: tag})
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			for _, sub := range formatFields(f.Type) {
+				sub.index = append([]int{i}, sub.index...)
+				fields = append(fields, sub)
+			}
+		}
+	}
+	return fields
+}
+
+// Returns a format string that prints all fields in a single line.
+func formatString(fields []formatField) string {
+	vars := make([]string, len(fields))
+	for i, f := range fields {
+		vars[i] = "#{" + f.name + "}"
+	}
+	return strings.Join(vars, formatSeparator)
+}
+
+// Decodes a line printed with formatString to the struct value.
+func decodeFormat(line string, v reflect.Value, fields []formatField) error {
+	values := strings.Split(line, formatSeparator)
+	if len(values) != len(fields) {
+		return fmt.Errorf("Unexpected format output: %q", line)
+	}
+	for i, f := range fields {
+		if err := setFormatValue(v.FieldByIndex(f.index), values[i]); err != nil {
+			return fmt.Errorf("Bad value of %s: %s", f.name, err)
+		}
+	}
+	return nil
+}
+
+// Sets the field to the value of format variable. Empty values are decoded to
+// zero values.
+func setFormatValue(v reflect.Value, value string) error {
+	if v.Type() == timeType {
+		if value == "" {
+			v.Set(reflect.ValueOf(time.Time{}))
+			return nil
+		}
+		sec, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(time.Unix(sec, 0)))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		v.SetBool(value != "" && value != "0")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Ids of sessions, windows and panes are prefixed with $, @ and %
+		value = strings.TrimLeft(value, "$@%")
+		if value == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			v.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Runs the list command (list-sessions, list-windows, list-panes etc) with
+// given arguments and decodes each line of the output to a new element of the
+// slice pointed to by dst. Elements of the slice are structs, which fields are
+// tagged with names of format variables:
+//
+//	type paneInfo struct {
+//		Id  int    `tmux:"pane_id"`
+//		Pid int    `tmux:"pane_pid"`
+//		Tty string `tmux:"pane_tty"`
+//	}
+//	var panes []paneInfo
+//	err := server.QueryList(ctx, &panes, "list-panes", "-a")
+//
+// Supported field types are string, integers, bool and time.Time, which is
+// decoded from Unix time. Fields of embedded structs are filled too.
+func (s *Server) QueryList(ctx context.Context, dst interface{}, args ...string) error {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("QueryList: dst must be a pointer to slice")
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("QueryList: dst must be a pointer to slice of structs")
+	}
+
+	fields := formatFields(elemType)
+	args = append(args, "-F", formatString(fields))
+	out, _, err := s.run(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		elem := reflect.New(elemType)
+		if err := decodeFormat(line, elem.Elem(), fields); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return nil
+}
+
+// Runs display-message for the target and decodes its output to the struct
+// pointed to by dst. See QueryList for supported struct fields.
+func (s *Server) QueryDisplay(ctx context.Context, target string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("QueryDisplay: dst must be a pointer to struct")
+	}
+
+	fields := formatFields(v.Elem().Type())
+	args := []string{"display-message", "-p"}
+	if target != "" {
+		args = append(args, "-t", target)
+	}
+	args = append(args, formatString(fields))
+	out, _, err := s.run(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	return decodeFormat(strings.TrimSuffix(out, "\n"), v.Elem(), fields)
+}
+
+// Runs the command that prints information about created object with -P -F
+// flags and decodes it to the struct pointed to by dst. The flags are placed
+// right after the command name, so args may end with positional arguments.
+func (s *Server) queryCreate(ctx context.Context, dst interface{}, args ...string) error {
+	v := reflect.ValueOf(dst).Elem()
+	fields := formatFields(v.Type())
+	flags := []string{"-P", "-F", formatString(fields)}
+	args = append(append([]string{args[0]}, flags...), args[1:]...)
+	out, _, err := s.run(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	return decodeFormat(strings.TrimSuffix(out, "\n"), v, fields)
+}
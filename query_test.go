@@ -0,0 +1,121 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+type queryInfo struct {
+	Id      int       `tmux:"pane_id"`
+	Name    string    `tmux:"window_name"`
+	Dead    bool      `tmux:"pane_dead"`
+	Created time.Time `tmux:"session_created"`
+	Ignored string
+	queryEmbedded
+}
+
+type queryEmbedded struct {
+	Width uint `tmux:"pane_width"`
+}
+
+func TestQueryList(t *testing.T) {
+	e := &fakeExecutor{out: "%1\x1fa:b c\x1f1\x1f1600000000\x1f80\n%2\x1f\x1f0\x1f\x1f\n"}
+	s := &Server{Executor: e}
+
+	var infos []queryInfo
+	if err := s.QueryList(context.Background(), &infos, "list-panes", "-a"); err != nil {
+		t.Fatalf("QueryList: %s", err)
+	}
+	expected := []queryInfo{
+		{Id: 1, Name: "a:b c", Dead: true, Created: time.Unix(1600000000, 0), queryEmbedded: queryEmbedded{Width: 80}},
+		{Id: 2},
+	}
+	if len(infos) != len(expected) {
+		t.Fatalf("Expected %d elements, got %d", len(expected), len(infos))
+	}
+	for i := range infos {
+		if infos[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], infos[i])
+		}
+	}
+
+	format := "#{pane_id}\x1f#{window_name}\x1f#{pane_dead}\x1f#{session_created}\x1f#{pane_width}"
+	args := e.calls[0]
+	if args[len(args)-2] != "-F" || args[len(args)-1] != format {
+		t.Fatalf("Unexpected arguments: %q", args)
+	}
+}
+
+func TestQueryDisplay(t *testing.T) {
+	e := &fakeExecutor{out: "bad\x1fvalue\n"}
+	s := &Server{Executor: e}
+
+	var info queryInfo
+	if err := s.QueryDisplay(context.Background(), "%1", &info); err == nil {
+		t.Fatalf("Malformed output was accepted")
+	}
+}
+
+func TestWindowNameWithSeparators(t *testing.T) {
+	s := createSession()
+	defer sessionsReaper(s.Name)
+
+	if _, err := s.NewWindow("name: with: colons"); err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	windows, err := s.ListWindows()
+	if err != nil {
+		t.Fatalf("ListWindows: %s", err)
+	}
+	found := false
+	for _, w := range windows {
+		if w.Name == "name: with: colons" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Can't find window with colons in the name: %+v", windows)
+	}
+}
+
+func TestQueryWithoutUTF8Locale(t *testing.T) {
+	// tmux does not assume UTF-8 without these variables
+	vars := []string{"LANG", "LC_ALL", "LC_CTYPE", "TMUX"}
+	saved := map[string]string{}
+	for _, name := range vars {
+		if value, ok := os.LookupEnv(name); ok {
+			saved[name] = value
+		}
+		os.Unsetenv(name)
+	}
+	defer func() {
+		for name, value := range saved {
+			os.Setenv(name, value)
+		}
+	}()
+	os.Setenv("LANG", "C")
+
+	server, session := createControlServer(t)
+	defer killServer(server)
+	if _, err := server.ListSessions(); err != nil {
+		t.Fatalf("ListSessions: %s", err)
+	}
+
+	client, err := server.NewControlClient(session.Name)
+	if err != nil {
+		t.Fatalf("NewControlClient: %s", err)
+	}
+	defer client.Close()
+	server.Executor = client
+	if _, err := session.NewWindow("test-locale-window"); err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	if _, err := server.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+}
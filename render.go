@@ -0,0 +1,112 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Rendering of panes and windows to HTML and SVG.
+
+package tmux
+
+import (
+	"context"
+
+	"github.com/jubnzv/go-tmux/screen"
+)
+
+// Renders the visible content of the pane as HTML with colors and attributes.
+func (p *Pane) RenderHTML() (string, error) {
+	return p.RenderHTMLContext(context.Background())
+}
+
+// Same as RenderHTML, but aborts the tmux command when ctx is done.
+func (p *Pane) RenderHTMLContext(ctx context.Context) (string, error) {
+	s, err := p.ScreenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.HTML(), nil
+}
+
+// Renders the visible content of the pane as SVG image.
+func (p *Pane) RenderSVG() (string, error) {
+	return p.RenderSVGContext(context.Background())
+}
+
+// Same as RenderSVG, but aborts the tmux command when ctx is done.
+func (p *Pane) RenderSVGContext(ctx context.Context) (string, error) {
+	s, err := p.ScreenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.SVG(), nil
+}
+
+// Returns the visible content of the window: screens of all panes placed
+// according to the window layout and separated with borders. If the window is
+// zoomed, only the active pane is visible. The cursor is at the position of
+// the cursor of the active pane.
+func (w *Window) Screen() (*screen.Screen, error) {
+	return w.ScreenContext(context.Background())
+}
+
+// Same as Screen, but aborts the tmux command when ctx is done.
+func (w *Window) ScreenContext(ctx context.Context) (*screen.Screen, error) {
+	var size struct {
+		Width  int  `tmux:"window_width"`
+		Height int  `tmux:"window_height"`
+		Zoomed bool `tmux:"window_zoomed_flag"`
+	}
+	if err := w.server.QueryDisplay(ctx, w.target(), &size); err != nil {
+		return nil, err
+	}
+	panes, err := w.server.listPanes(ctx, []string{"-t", w.target()})
+	if err != nil {
+		return nil, err
+	}
+
+	var placements []screen.Placement
+	var cursorX, cursorY int
+	for i := range panes {
+		if size.Zoomed && !panes[i].Active {
+			continue
+		}
+		s, err := panes[i].ScreenContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		placements = append(placements, screen.Placement{Screen: s, X: panes[i].Left, Y: panes[i].Top})
+		if panes[i].Active {
+			cursorX, cursorY = panes[i].Left+s.CursorX, panes[i].Top+s.CursorY
+		}
+	}
+
+	s := screen.Compose(size.Width, size.Height, placements)
+	s.CursorX, s.CursorY = cursorX, cursorY
+	return s, nil
+}
+
+// Renders the visible content of the window with pane borders as HTML.
+func (w *Window) RenderHTML() (string, error) {
+	return w.RenderHTMLContext(context.Background())
+}
+
+// Same as RenderHTML, but aborts the tmux command when ctx is done.
+func (w *Window) RenderHTMLContext(ctx context.Context) (string, error) {
+	s, err := w.ScreenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.HTML(), nil
+}
+
+// Renders the visible content of the window with pane borders as SVG image.
+func (w *Window) RenderSVG() (string, error) {
+	return w.RenderSVGContext(context.Background())
+}
+
+// Same as RenderSVG, but aborts the tmux command when ctx is done.
+func (w *Window) RenderSVGContext(ctx context.Context) (string, error) {
+	s, err := w.ScreenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.SVG(), nil
+}
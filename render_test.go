@@ -0,0 +1,84 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWindowRender(t *testing.T) {
+	s := NewServer("", "go-tmux-"+t.Name(), nil)
+	defer killServer(s)
+	session, err := s.NewSession("test-render")
+	if err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	windows, err := session.ListWindows()
+	if err != nil || len(windows) != 1 {
+		t.Fatalf("ListWindows: %v", err)
+	}
+	window := windows[0]
+	panes, err := session.ListPanes()
+	if err != nil || len(panes) != 1 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	pane, err := panes[0].Split(SplitOptions{Direction: SplitHorizontal, Command: "sh"})
+	if err != nil {
+		t.Fatalf("Split: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := pane.Exec(ctx, `printf '\033[32m%s%s\033[0m\n' gre en`); err != nil {
+		t.Fatalf("Exec: %s", err)
+	}
+
+	sc, err := window.Screen()
+	if err != nil {
+		t.Fatalf("Screen: %s", err)
+	}
+	if sc.Width != window.Width || sc.Height != window.Height {
+		t.Fatalf("Unexpected screen size: %dx%d", sc.Width, sc.Height)
+	}
+	if c := sc.Cell(pane.Left-1, 0); c.Rune != '│' {
+		t.Fatalf("Expected border, got %q", c.Rune)
+	}
+	x, y, ok := sc.Find("green")
+	if !ok || x != pane.Left || sc.CursorX < pane.Left || sc.CursorY <= y {
+		t.Fatalf("Unexpected position of text %d, %d or cursor %d, %d",
+			x, y, sc.CursorX, sc.CursorY)
+	}
+
+	html, err := window.RenderHTML()
+	if err != nil {
+		t.Fatalf("RenderHTML: %s", err)
+	}
+	if !strings.Contains(html, `<span style="color:#00cd00">green</span>`) {
+		t.Fatalf("Unexpected HTML: %s", html)
+	}
+	svg, err := pane.RenderSVG()
+	if err != nil {
+		t.Fatalf("RenderSVG: %s", err)
+	}
+	if !strings.Contains(svg, `fill="#00cd00" textLength="42.0" lengthAdjust="spacingAndGlyphs">green</tspan>`) {
+		t.Fatalf("Unexpected SVG: %s", svg)
+	}
+	// Only the zoomed pane is visible
+	if _, _, err := s.run(ctx, []string{"resize-pane", "-Z", "-t", pane.target()}); err != nil {
+		t.Fatalf("resize-pane: %s", err)
+	}
+	sc, err = window.Screen()
+	if err != nil {
+		t.Fatalf("Screen: %s", err)
+	}
+	if strings.ContainsRune(sc.String(), '│') {
+		t.Fatalf("Unexpected border in zoomed window:\n%s", sc.String())
+	}
+	if x, _, ok := sc.Find("green"); !ok || x != 0 {
+		t.Fatalf("Unexpected position of text in zoomed window: %d, %v", x, ok)
+	}
+}
@@ -0,0 +1,82 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package screen
+
+// Screen of the pane placed at the position of the composed screen.
+type Placement struct {
+	Screen *Screen
+	X      int
+	Y      int
+}
+
+// Returns an empty screen filled with spaces.
+func New(width, height int) *Screen {
+	return Parse("", width, height)
+}
+
+// Composes screens of panes to a screen of the window. Cells not covered by
+// any pane are drawn as pane borders.
+func Compose(width, height int, placements []Placement) *Screen {
+	s := New(width, height)
+	covered := make([][]bool, height)
+	for y := range covered {
+		covered[y] = make([]bool, width)
+	}
+
+	for _, p := range placements {
+		for y, row := range p.Screen.Cells {
+			for x, c := range row {
+				sx, sy := p.X+x, p.Y+y
+				if sx < 0 || sy < 0 || sx >= width || sy >= height {
+					continue
+				}
+				s.Cells[sy][sx] = c
+				covered[sy][sx] = true
+			}
+		}
+	}
+
+	border := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < width && y < height && !covered[y][x]
+	}
+	for y := range covered {
+		for x := range covered[y] {
+			if covered[y][x] {
+				continue
+			}
+			var mask int
+			if border(x, y-1) {
+				mask |= borderUp
+			}
+			if border(x, y+1) {
+				mask |= borderDown
+			}
+			if border(x-1, y) {
+				mask |= borderLeft
+			}
+			if border(x+1, y) {
+				mask |= borderRight
+			}
+			s.Cells[y][x].Rune = borderRunes[mask]
+		}
+	}
+
+	return s
+}
+
+// Directions where the border continues from the cell.
+const (
+	borderUp = 1 << iota
+	borderDown
+	borderLeft
+	borderRight
+)
+
+// Box drawing characters for each combination of border directions.
+var borderRunes = [16]rune{
+	' ', '│', '│', '│',
+	'─', '┘', '┐', '┤',
+	'─', '└', '┌', '├',
+	'─', '┴', '┬', '┼',
+}
@@ -0,0 +1,241 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package screen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Current text style while parsing.
+type style struct {
+	fg    Color
+	bg    Color
+	attrs Attr
+}
+
+// Parses the content captured with escape sequences. Rows are padded with
+// spaces or truncated to width and the screen is padded with empty rows or
+// truncated to height. If width or height is zero, it is calculated from the
+// content. Escape sequences other than SGR are ignored.
+func Parse(content string, width, height int) *Screen {
+	var st style
+	var rows [][]Cell
+	content = strings.TrimSuffix(content, "\n")
+	if content != "" {
+		for _, line := range strings.Split(content, "\n") {
+			rows = append(rows, st.parseLine(line))
+		}
+	}
+
+	if width == 0 {
+		for _, row := range rows {
+			if len(row) > width {
+				width = len(row)
+			}
+		}
+	}
+	if height == 0 {
+		height = len(rows)
+	}
+	if len(rows) > height {
+		rows = rows[:height]
+	}
+	for len(rows) < height {
+		rows = append(rows, nil)
+	}
+	for y, row := range rows {
+		if len(row) > width {
+			row = row[:width]
+		}
+		for len(row) < width {
+			row = append(row, Cell{Rune: ' ', Width: 1})
+		}
+		rows[y] = row
+	}
+
+	return &Screen{Width: width, Height: height, Cells: rows}
+}
+
+// Parses a single line. The style is kept between lines, like tmux does when
+// printing the captured content.
+func (st *style) parseLine(line string) []Cell {
+	row := []Cell{}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == 0x1b {
+			i = st.parseEscape(runes, i)
+			continue
+		}
+		if r < 0x20 || r == 0x7f {
+			continue
+		}
+
+		w := runeWidth(r)
+		if w == 0 {
+			// Combine with the last character
+			for j := len(row) - 1; j >= 0; j-- {
+				if row[j].Width != 0 {
+					row[j].Combining = append(row[j].Combining, r)
+					break
+				}
+			}
+			continue
+		}
+		row = append(row, Cell{Rune: r, Width: w, Fg: st.fg, Bg: st.bg, Attrs: st.attrs})
+		if w == 2 {
+			row = append(row, Cell{Fg: st.fg, Bg: st.bg, Attrs: st.attrs})
+		}
+	}
+	return row
+}
+
+// Parses the escape sequence starting at runes[i] and returns index of its
+// last character.
+func (st *style) parseEscape(runes []rune, i int) int {
+	if i+1 >= len(runes) {
+		return i
+	}
+	switch runes[i+1] {
+	case '[': // CSI: parameters followed by a final byte
+		j := i + 2
+		for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+			j++
+		}
+		if j < len(runes) && runes[j] == 'm' {
+			st.applySGR(string(runes[i+2 : j]))
+		}
+		return j
+	case ']': // OSC: terminated with BEL or ST
+		for j := i + 2; j < len(runes); j++ {
+			if runes[j] == 0x07 {
+				return j
+			}
+			if runes[j] == 0x1b && j+1 < len(runes) && runes[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(runes) - 1
+	}
+	return i + 1
+}
+
+// Applies parameters of Select Graphic Rendition sequence to the style.
+func (st *style) applySGR(params string) {
+	if params == "" {
+		*st = style{}
+		return
+	}
+
+	ps := strings.Split(params, ";")
+	for i := 0; i < len(ps); i++ {
+		sub := strings.Split(ps[i], ":")
+		code := atoi(sub[0])
+		switch {
+		case code == 0:
+			*st = style{}
+		case code == 1:
+			st.attrs |= AttrBold
+		case code == 2:
+			st.attrs |= AttrDim
+		case code == 3:
+			st.attrs |= AttrItalic
+		case code == 4:
+			// Underline style may be given as a subparameter, 4:0 is no
+			// underline
+			if len(sub) > 1 && atoi(sub[1]) == 0 {
+				st.attrs &^= AttrUnderline
+			} else {
+				st.attrs |= AttrUnderline
+			}
+		case code == 5 || code == 6:
+			st.attrs |= AttrBlink
+		case code == 7:
+			st.attrs |= AttrReverse
+		case code == 8:
+			st.attrs |= AttrHidden
+		case code == 9:
+			st.attrs |= AttrStrikethrough
+		case code == 21:
+			st.attrs |= AttrUnderline
+		case code == 22:
+			st.attrs &^= AttrBold | AttrDim
+		case code == 23:
+			st.attrs &^= AttrItalic
+		case code == 24:
+			st.attrs &^= AttrUnderline
+		case code == 25:
+			st.attrs &^= AttrBlink
+		case code == 27:
+			st.attrs &^= AttrReverse
+		case code == 28:
+			st.attrs &^= AttrHidden
+		case code == 29:
+			st.attrs &^= AttrStrikethrough
+		case code >= 30 && code <= 37:
+			st.fg = Indexed(uint8(code - 30))
+		case code == 38:
+			st.fg, i = extendedColor(ps, sub, i)
+		case code == 39:
+			st.fg = Color{}
+		case code >= 40 && code <= 47:
+			st.bg = Indexed(uint8(code - 40))
+		case code == 48:
+			st.bg, i = extendedColor(ps, sub, i)
+		case code == 49:
+			st.bg = Color{}
+		case code >= 90 && code <= 97:
+			st.fg = Indexed(uint8(code - 90 + 8))
+		case code >= 100 && code <= 107:
+			st.bg = Indexed(uint8(code - 100 + 8))
+		}
+	}
+}
+
+// Parses 256 or true color given either with subparameters (38:5:n,
+// 38:2::r:g:b) or with following parameters (38;5;n, 38;2;r;g;b). Returns the
+// color and index of the last consumed parameter.
+func extendedColor(ps []string, sub []string, i int) (Color, int) {
+	args := sub[1:]
+	consumed := false
+	if len(args) == 0 {
+		args = ps[i+1:]
+		consumed = true
+	}
+	if len(args) == 0 {
+		return Color{}, i
+	}
+
+	var c Color
+	n := 0
+	switch atoi(args[0]) {
+	case 5:
+		if len(args) < 2 {
+			return Color{}, len(ps) - 1
+		}
+		c, n = Indexed(uint8(atoi(args[1]))), 2
+	case 2:
+		// Subparameters may include the color space id: 38:2:id:r:g:b
+		if !consumed && len(args) > 4 {
+			args = args[1:]
+		}
+		if len(args) < 4 {
+			return Color{}, len(ps) - 1
+		}
+		c, n = RGB(uint8(atoi(args[1])), uint8(atoi(args[2])), uint8(atoi(args[3]))), 4
+	default:
+		return Color{}, i
+	}
+	if consumed {
+		i += n
+	}
+	return c, i
+}
+
+// Returns the parameter value, empty parameters are zero.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,238 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package screen
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Options of rendering of the screen to HTML and SVG.
+type RenderOptions struct {
+	Foreground string // Color of cells with default foreground, "#e5e5e5" if empty
+	Background string // Color of cells with default background, "#000000" if empty
+}
+
+// Returns the options with empty fields set to default values.
+func (o RenderOptions) withDefaults() RenderOptions {
+	if o.Foreground == "" {
+		o.Foreground = "#e5e5e5"
+	}
+	if o.Background == "" {
+		o.Background = "#000000"
+	}
+	return o
+}
+
+// Size of the cell and the font in SVG images in pixels.
+const (
+	svgCellWidth  = 8.4
+	svgCellHeight = 17
+	svgFontSize   = 14
+	svgBaseline   = 13 // Offset of the text baseline from the top of the cell
+)
+
+// Colors of the first 16 palette entries, as in xterm.
+var basicColors = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// Returns the color in #rrggbb format or an empty string for the default
+// color. Palette colors are converted using the xterm palette.
+func (c Color) Hex() string {
+	switch c.Type {
+	case ColorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	case ColorIndexed:
+		i := int(c.Index)
+		switch {
+		case i < 16:
+			return basicColors[i]
+		case i < 232:
+			// 6x6x6 color cube
+			levels := [6]int{0, 95, 135, 175, 215, 255}
+			i -= 16
+			return fmt.Sprintf("#%02x%02x%02x", levels[i/36], levels[i/6%6], levels[i%6])
+		default:
+			v := 8 + (i-232)*10
+			return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+		}
+	}
+	return ""
+}
+
+// Returns the foreground and background colors of the cell, taking reverse
+// and hidden attributes into account.
+func (c Cell) colors(o RenderOptions) (fg, bg string) {
+	fg, bg = c.Fg.Hex(), c.Bg.Hex()
+	if fg == "" {
+		fg = o.Foreground
+	}
+	if bg == "" {
+		bg = o.Background
+	}
+	if c.Has(AttrReverse) {
+		fg, bg = bg, fg
+	}
+	if c.Has(AttrHidden) {
+		fg = bg
+	}
+	return fg, bg
+}
+
+// Sequence of cells with the same style in a row.
+type run struct {
+	x     int    // First column
+	width int    // Number of columns
+	text  string // Text of the cells
+	style Cell   // First cell of the run
+}
+
+// Splits the row to runs of cells with the same style.
+func runs(row []Cell) []run {
+	var result []run
+	var text strings.Builder
+	for x, c := range row {
+		if len(result) == 0 || !sameStyle(result[len(result)-1].style, c) {
+			if len(result) > 0 {
+				result[len(result)-1].text = text.String()
+				text.Reset()
+			}
+			result = append(result, run{x: x, style: c})
+		}
+		result[len(result)-1].width++
+		text.WriteString(c.String())
+	}
+	if len(result) > 0 {
+		result[len(result)-1].text = text.String()
+	}
+	return result
+}
+
+// Returns true if the cells are rendered with the same style.
+func sameStyle(a, b Cell) bool {
+	return a.Fg == b.Fg && a.Bg == b.Bg && a.Attrs == b.Attrs
+}
+
+// Returns text decoration for underline and strikethrough attributes.
+func decoration(c Cell) string {
+	var d []string
+	if c.Has(AttrUnderline) {
+		d = append(d, "underline")
+	}
+	if c.Has(AttrStrikethrough) {
+		d = append(d, "line-through")
+	}
+	return strings.Join(d, " ")
+}
+
+// Renders the screen as HTML <pre> element with inline styles using default
+// options.
+func (s *Screen) HTML() string {
+	return s.HTMLWithOptions(RenderOptions{})
+}
+
+// Renders the screen as HTML <pre> element with inline styles.
+func (s *Screen) HTMLWithOptions(opts RenderOptions) string {
+	opts = opts.withDefaults()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<pre style="color:%s;background-color:%s;font-family:monospace">`,
+		opts.Foreground, opts.Background)
+	for y, row := range s.Cells {
+		if y > 0 {
+			b.WriteString("\n")
+		}
+		for _, r := range runs(row) {
+			css := htmlStyle(r.style, opts)
+			if css == "" {
+				b.WriteString(html.EscapeString(r.text))
+				continue
+			}
+			fmt.Fprintf(&b, `<span style="%s">%s</span>`, css, html.EscapeString(r.text))
+		}
+	}
+	b.WriteString("</pre>")
+	return b.String()
+}
+
+// Returns inline CSS for the cell or an empty string for the default style.
+func htmlStyle(c Cell, o RenderOptions) string {
+	var css []string
+	fg, bg := c.colors(o)
+	if fg != o.Foreground {
+		css = append(css, "color:"+fg)
+	}
+	if bg != o.Background {
+		css = append(css, "background-color:"+bg)
+	}
+	if c.Has(AttrBold) {
+		css = append(css, "font-weight:bold")
+	}
+	if c.Has(AttrDim) {
+		css = append(css, "opacity:0.5")
+	}
+	if c.Has(AttrItalic) {
+		css = append(css, "font-style:italic")
+	}
+	if d := decoration(c); d != "" {
+		css = append(css, "text-decoration:"+d)
+	}
+	return strings.Join(css, ";")
+}
+
+// Renders the screen as SVG image using default options.
+func (s *Screen) SVG() string {
+	return s.SVGWithOptions(RenderOptions{})
+}
+
+// Renders the screen as SVG image.
+func (s *Screen) SVGWithOptions(opts RenderOptions) string {
+	opts = opts.withDefaults()
+	var b strings.Builder
+	width, height := float64(s.Width)*svgCellWidth, s.Height*svgCellHeight
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.1f" height="%d" `+
+		`font-family="monospace" font-size="%d">`, width, height, svgFontSize)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, opts.Background)
+
+	for y, row := range s.Cells {
+		rs := runs(row)
+		for _, r := range rs {
+			if _, bg := r.style.colors(opts); bg != opts.Background {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`,
+					float64(r.x)*svgCellWidth, y*svgCellHeight,
+					float64(r.width)*svgCellWidth, svgCellHeight, bg)
+			}
+		}
+
+		fmt.Fprintf(&b, `<text y="%d" xml:space="preserve">`, y*svgCellHeight+svgBaseline)
+		for _, r := range rs {
+			if strings.TrimSpace(r.text) == "" && decoration(r.style) == "" {
+				continue
+			}
+			fg, _ := r.style.colors(opts)
+			// Text length keeps wide characters aligned to the grid
+			fmt.Fprintf(&b, `<tspan x="%.1f" fill="%s" textLength="%.1f" lengthAdjust="spacingAndGlyphs"`,
+				float64(r.x)*svgCellWidth, fg, float64(r.width)*svgCellWidth)
+			if r.style.Has(AttrBold) {
+				b.WriteString(` font-weight="bold"`)
+			}
+			if r.style.Has(AttrDim) {
+				b.WriteString(` opacity="0.5"`)
+			}
+			if r.style.Has(AttrItalic) {
+				b.WriteString(` font-style="italic"`)
+			}
+			if d := decoration(r.style); d != "" {
+				fmt.Fprintf(&b, ` text-decoration="%s"`, d)
+			}
+			fmt.Fprintf(&b, `>%s</tspan>`, html.EscapeString(r.text))
+		}
+		b.WriteString("</text>")
+	}
+
+	b.WriteString("</svg>")
+	return b.String()
+}
@@ -0,0 +1,79 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColorHex(t *testing.T) {
+	cases := []struct {
+		c   Color
+		hex string
+	}{
+		{Color{}, ""},
+		{Indexed(Red), "#cd0000"},
+		{Indexed(Red + 8), "#ff0000"},
+		{Indexed(196), "#ff0000"},
+		{Indexed(232), "#080808"},
+		{RGB(1, 2, 255), "#0102ff"},
+	}
+	for _, c := range cases {
+		if hex := c.c.Hex(); hex != c.hex {
+			t.Errorf("Incorrect color of %+v (expected %q got %q)", c.c, c.hex, hex)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	s := Parse("a\x1b[1;31m<b>\x1b[0m\n\x1b[7mr\n", 5, 2)
+	expected := `<pre style="color:#e5e5e5;background-color:#000000;font-family:monospace">` +
+		`a<span style="color:#cd0000;font-weight:bold">&lt;b&gt;</span> ` + "\n" +
+		`<span style="color:#000000;background-color:#e5e5e5">r</span>    </pre>`
+	if html := s.HTML(); html != expected {
+		t.Fatalf("Unexpected HTML:\n%s\nexpected:\n%s", html, expected)
+	}
+}
+
+func TestRenderOptions(t *testing.T) {
+	s := Parse("a\x1b[7mb\n", 2, 1)
+	opts := RenderOptions{Foreground: "#111111", Background: "#eeeeee"}
+	expected := `<pre style="color:#111111;background-color:#eeeeee;font-family:monospace">` +
+		`a<span style="color:#eeeeee;background-color:#111111">b</span></pre>`
+	if html := s.HTMLWithOptions(opts); html != expected {
+		t.Fatalf("Unexpected HTML:\n%s\nexpected:\n%s", html, expected)
+	}
+	if svg := s.SVGWithOptions(opts); !strings.Contains(svg, `<rect width="100%" height="100%" fill="#eeeeee"/>`) {
+		t.Fatalf("Unexpected background of SVG:\n%s", svg)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	s := Parse("a\x1b[42m漢\x1b[0m \n", 4, 1)
+	svg := s.SVG()
+	for _, part := range []string{
+		`width="33.6" height="17"`,
+		`<rect x="8.4" y="0" width="16.8" height="17" fill="#00cd00"/>`,
+		`<tspan x="0.0" fill="#e5e5e5" textLength="8.4" lengthAdjust="spacingAndGlyphs">a</tspan>`,
+		`<tspan x="8.4" fill="#e5e5e5" textLength="16.8" lengthAdjust="spacingAndGlyphs">漢</tspan>`,
+	} {
+		if !strings.Contains(svg, part) {
+			t.Fatalf("SVG does not contain %q:\n%s", part, svg)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	// Left pane and two panes stacked on the right
+	s := Compose(5, 3, []Placement{
+		{Screen: Parse("a\na\na\n", 2, 3), X: 0, Y: 0},
+		{Screen: Parse("b\n", 2, 1), X: 3, Y: 0},
+		{Screen: Parse("c\n", 2, 1), X: 3, Y: 2},
+	})
+	expected := "a │b\na ├──\na │c"
+	if s.String() != expected {
+		t.Fatalf("Unexpected screen:\n%s\nexpected:\n%s", s.String(), expected)
+	}
+}
@@ -0,0 +1,109 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package screen
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := "\x1b[1m\x1b[31mred\x1b[0m\x1b[39m\x1b[49m \x1b[4m\x1b[38;5;200mx" +
+		"\x1b[48;2;1;2;3m漢\n\x1b[0m\x1b[39m\x1b[49m# é\n"
+	s := Parse(content, 10, 3)
+	if s.Width != 10 || s.Height != 3 || len(s.Cells) != 3 || len(s.Cells[0]) != 10 {
+		t.Fatalf("Unexpected screen size: %dx%d", s.Width, s.Height)
+	}
+
+	if c := s.Cell(0, 0); c.Rune != 'r' || c.Fg != Indexed(Red) || !c.Has(AttrBold) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if c := s.Cell(3, 0); c.Rune != ' ' || c.Fg != (Color{}) || c.Attrs != 0 {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if c := s.Cell(4, 0); c.Fg != Indexed(200) || !c.Has(AttrUnderline) || c.Has(AttrBold) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if c := s.Cell(5, 0); c.Rune != '漢' || c.Width != 2 || c.Bg != RGB(1, 2, 3) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+	if c := s.Cell(6, 0); c.Width != 0 {
+		t.Fatalf("Expected placeholder of the wide character, got %+v", c)
+	}
+	if c := s.Cell(2, 1); c.String() != "é" || c.Bg != (Color{}) {
+		t.Fatalf("Unexpected cell: %+v", c)
+	}
+
+	if s.Line(0) != "red x漢" || s.Line(1) != "# é" || s.Line(2) != "" {
+		t.Fatalf("Unexpected text: %q", s.String())
+	}
+	if x, y, ok := s.Find("x漢"); !ok || x != 4 || y != 0 {
+		t.Fatalf("Unexpected position: %d, %d, %v", x, y, ok)
+	}
+	if _, _, ok := s.Find("blue"); ok {
+		t.Fatalf("Found missing text")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	s := Parse("ab\nabcd\n", 0, 0)
+	if s.Width != 4 || s.Height != 2 {
+		t.Fatalf("Unexpected screen size: %dx%d", s.Width, s.Height)
+	}
+	s = Parse("abcd\nb\nc\n", 2, 1)
+	if s.String() != "ab" {
+		t.Fatalf("Screen was not truncated: %q", s.String())
+	}
+}
+
+func TestApplySGR(t *testing.T) {
+	cases := []struct {
+		params string
+		st     style
+	}{
+		{"", style{}},
+		{"1;2;3;4;5;7;8;9", style{attrs: AttrBold | AttrDim | AttrItalic | AttrUnderline |
+			AttrBlink | AttrReverse | AttrHidden | AttrStrikethrough}},
+		{"4:3", style{attrs: AttrUnderline}},
+		{"4;4:0", style{}},
+		{"1;22", style{}},
+		{"38:5:9;48:2::10:20:30", style{fg: Indexed(9), bg: RGB(10, 20, 30)}},
+		{"38;2;10;20;30;1", style{fg: RGB(10, 20, 30), attrs: AttrBold}},
+		{"92;103", style{fg: Indexed(10), bg: Indexed(11)}},
+		{"31;39;41;49", style{}},
+		{"38;5", style{}},
+	}
+	for _, c := range cases {
+		var st style
+		st.applySGR(c.params)
+		if st != c.st {
+			t.Errorf("Unexpected style for %q: %+v", c.params, st)
+		}
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	for r, width := range map[rune]int{
+		'a':      1,
+		'é':      1,
+		'\u0301': 0,
+		'漢':      2,
+		'\uff71': 1,
+		'🚀':      2,
+		'🀄':      2,
+		'⌚':      2,
+		'⭐':      2,
+		'☔':      2,
+		'🩰':      2,
+		'→':      1,
+	} {
+		if w := runeWidth(r); w != width {
+			t.Errorf("Expected width %d of %q, got %d", width, r, w)
+		}
+	}
+
+	s := Parse("🚀x\n", 4, 1)
+	if c := s.Cell(0, 0); c.Rune != '🚀' || c.Width != 2 || s.Cell(2, 0).Rune != 'x' {
+		t.Fatalf("Unexpected cells: %+v", s.Cells[0])
+	}
+}
@@ -7,10 +7,8 @@
 package tmux
 
 import (
+	"context"
 	"errors"
-	"regexp"
-	"strconv"
-	"strings"
 )
 
 // Represents a tmux server:
@@ -19,6 +17,7 @@ type Server struct {
 	SocketPath string    // Path to tmux server socket
 	SocketName string    // Name of created tmux socket
 	Sessions   []Session // List of sessions used on server initialization
+	Executor   Executor  // Used to run tmux commands. If nil, DefaultExecutor is used
 }
 
 // Creates a new server object.
@@ -30,37 +29,57 @@ func NewServer(socketPath, socketName string, sessions []Session) *Server {
 	}
 }
 
-// Lists all sessions managed by this server.
-func (s *Server) ListSessions() ([]Session, error) {
-	args := []string{
-		"list-sessions",
-		"-F", "#{session_id}:#{session_name}"}
-	if s.SocketPath != "" {
-		args = append([]string{"-S", s.SocketPath}, args...)
+// Returns tmux flags that select the socket of this server. It is safe to call
+// on nil server, in that case the default socket is used.
+func (s *Server) socketArgs() []string {
+	args := []string{}
+	if s == nil {
+		return args
 	}
 	if s.SocketName != "" {
-		args = append([]string{"-L", s.SocketName}, args...)
+		args = append(args, "-L", s.SocketName)
 	}
+	if s.SocketPath != "" {
+		args = append(args, "-S", s.SocketPath)
+	}
+	return args
+}
 
-	out, _, err := RunCmd(args)
-	if err != nil {
-		return nil, err
+// Runs tmux command with given arguments using executor of this server. It is
+// safe to call on nil server, in that case DefaultExecutor is used.
+//
+// Errors of failed commands are wrapped in TmuxError.
+func (s *Server) run(ctx context.Context, args []string) (string, string, error) {
+	var e Executor = DefaultExecutor{}
+	if s != nil && s.Executor != nil {
+		e = s.Executor
 	}
+	args = append(s.socketArgs(), args...)
 
-	outLines := strings.Split(out, "\n")
-	sessions := []Session{}
-	re := regexp.MustCompile(`\$([0-9]+):(.+)`)
-	for _, line := range outLines {
-		result := re.FindStringSubmatch(line)
-		if len(result) < 3 {
-			continue
-		}
-		id, err_atoi := strconv.Atoi(result[1])
-		if err_atoi != nil {
-			return nil, err_atoi
+	out, errOut, err := e.Run(ctx, args)
+	if err != nil && ctx.Err() == nil {
+		var tmuxErr *TmuxError
+		if !errors.As(err, &tmuxErr) {
+			err = newTmuxError(args, errOut, err)
 		}
+	}
+
+	return out, errOut, err
+}
+
+// Lists all sessions managed by this server.
+func (s *Server) ListSessions() ([]Session, error) {
+	return s.ListSessionsContext(context.Background())
+}
 
-		sessions = append(sessions, Session{Name: result[2], Id: id})
+// Same as ListSessions, but aborts the tmux command when ctx is done.
+func (s *Server) ListSessionsContext(ctx context.Context) ([]Session, error) {
+	sessions := []Session{}
+	if err := s.QueryList(ctx, &sessions, "list-sessions"); err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].server = s
 	}
 
 	return sessions, nil
@@ -77,8 +96,14 @@ func (s *Server) AddSession(session Session) {
 //
 // Session always will be detached after creation. Call AttachSession to attach
 // it. If session already exists, this function return an error. Check session
-// with HaveSession before running it if you need it.
+// with HasSession before running it if you need it. The returned error matches
+// ErrDuplicateSession in this case.
 func (s *Server) NewSession(name string) (session Session, err error) {
+	return s.NewSessionContext(context.Background(), name)
+}
+
+// Same as NewSession, but aborts the tmux command when ctx is done.
+func (s *Server) NewSessionContext(ctx context.Context, name string) (session Session, err error) {
 	if checkSessionName(name) == false {
 		return session, errors.New("Bad session name")
 	}
@@ -87,35 +112,23 @@ func (s *Server) NewSession(name string) (session Session, err error) {
 		"new-session",
 		"-d",
 		"-D",
-		"-s", name,
-		"-P", "-F", "#{session_id}:#{session_name}"}
-
-	out, err_out, err_exec := RunCmd(args)
-	if err_exec != nil {
-		// It's okay, if session already exists.
-		if !strings.Contains(err_out, "exit status 1") {
-			return session, err_exec
-		}
-	}
-
-	re := regexp.MustCompile(`\$([0-9]+):(.+)`)
-	result := re.FindStringSubmatch(out)
-	if len(result) < 3 {
-		return session, errors.New("Error creating session")
+		"-s", name}
+	if err := s.queryCreate(ctx, &session, args...); err != nil {
+		return session, err
 	}
 
-	id, err_atoi := strconv.Atoi(result[1])
-	if err_atoi != nil {
-		return session, err_atoi
-	}
-
-	session = Session{Name: result[2], Id: id}
+	session.server = s
 	return session, nil
 }
 
 // Kills session with given name. If killed session not found, KillSession will
 // not raise error and just do nothing.
 func (s *Server) KillSession(name string) error {
+	return s.KillSessionContext(context.Background(), name)
+}
+
+// Same as KillSession, but aborts the tmux command when ctx is done.
+func (s *Server) KillSessionContext(ctx context.Context, name string) error {
 	// Running "kill-session" without name causes killing attached session.
 	if checkSessionName(name) == false {
 		return errors.New("KillSession: Bad session name")
@@ -123,7 +136,10 @@ func (s *Server) KillSession(name string) error {
 
 	args := []string{"kill-session", "-t", name}
 
-	if _, _, err := RunCmd(args); err != nil {
+	if _, _, err := s.run(ctx, args); err != nil {
+		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrNoServer) {
+			return nil
+		}
 		return err
 	}
 
@@ -133,14 +149,19 @@ func (s *Server) KillSession(name string) error {
 // Return true that session with given name is exsits on this server, false
 // otherwise.
 func (s *Server) HasSession(name string) (bool, error) {
+	return s.HasSessionContext(context.Background(), name)
+}
+
+// Same as HasSession, but aborts the tmux command when ctx is done.
+func (s *Server) HasSessionContext(ctx context.Context, name string) (bool, error) {
 	if checkSessionName(name) == false {
 		return false, errors.New("Bad session name")
 	}
 
 	args := []string{"has-session", "-t", name}
 
-	_, err_out, err := RunCmd(args)
-	if strings.Contains(err_out, "can't find session") {
+	_, _, err := s.run(ctx, args)
+	if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrNoServer) {
 		return false, nil
 	}
 	if err != nil {
@@ -152,5 +173,10 @@ func (s *Server) HasSession(name string) (bool, error) {
 
 // Return list with all panes managed by this server.
 func (s *Server) ListPanes() ([]Pane, error) {
-	return ListPanes([]string{"-a"})
+	return s.ListPanesContext(context.Background())
+}
+
+// Same as ListPanes, but aborts the tmux command when ctx is done.
+func (s *Server) ListPanesContext(ctx context.Context) ([]Pane, error) {
+	return s.listPanes(ctx, []string{"-a"})
 }
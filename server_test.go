@@ -4,7 +4,11 @@
 package tmux
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestListSessions(t *testing.T) {
@@ -87,3 +91,122 @@ func TestKillSession(t *testing.T) {
 		t.Fatalf("KillSession: Can't kill 'test-kill-session' session!")
 	}
 }
+
+// Executor that records executed commands and returns prepared output.
+type fakeExecutor struct {
+	calls [][]string
+	out   string
+}
+
+func (e *fakeExecutor) Run(ctx context.Context, args []string) (string, string, error) {
+	e.calls = append(e.calls, args)
+	return e.out, "", nil
+}
+
+func TestServerExecutor(t *testing.T) {
+	fields := formatFields(reflect.TypeOf(Session{}))
+	e := &fakeExecutor{out: "$1" + formatSeparator + "fake-session" +
+		strings.Repeat(formatSeparator, len(fields)-2) + "\n"}
+	s := &Server{Executor: e}
+	sessions, err := s.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %s", err)
+	}
+	if len(sessions) != 1 || sessions[0].Name != "fake-session" {
+		t.Fatalf("Unexpected sessions: %v", sessions)
+	}
+
+	// Objects obtained from the server must use its executor.
+	fields = formatFields(reflect.TypeOf(struct {
+		Window
+		Pane
+	}{}))
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		switch f.name {
+		case "window_id":
+			values[i] = "@3"
+		case "window_name":
+			values[i] = "fake-window"
+		}
+	}
+	e.out = strings.Join(values, formatSeparator) + "\n"
+	window, err := sessions[0].NewWindow("fake-window")
+	if err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	if window.Id != 3 || window.Name != "fake-window" {
+		t.Fatalf("Unexpected window: %+v", window)
+	}
+	if len(e.calls) != 2 || e.calls[1][0] != "new-window" || e.calls[1][1] != "-P" {
+		t.Fatalf("Unexpected executor calls: %v", e.calls)
+	}
+
+	e.out = ""
+	if _, err := sessions[0].ListPanes(); err != nil {
+		t.Fatalf("ListPanes: %s", err)
+	}
+	if len(e.calls) != 3 || e.calls[2][0] != "list-panes" {
+		t.Fatalf("Unexpected executor calls: %v", e.calls)
+	}
+}
+
+func TestListSessionsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := new(Server)
+	if _, err := s.ListSessionsContext(ctx); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestServerSocketName(t *testing.T) {
+	s := NewServer("", "go-tmux-test", nil)
+	defer killServer(s)
+
+	session, err := s.NewSession("test-socket-session")
+	if err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	if _, err := session.NewWindow("test-socket-window"); err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	windows, err := session.ListWindows()
+	if err != nil {
+		t.Fatalf("ListWindows: %s", err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("Expected 2 windows, got %d", len(windows))
+	}
+
+	// Session must not be visible on the default server
+	if has, _ := new(Server).HasSession("test-socket-session"); has {
+		t.Fatalf("Session was created on the default server")
+	}
+}
+
+func TestListSessionsState(t *testing.T) {
+	s := new(Server)
+	if _, err := s.NewSession("test-session-state"); err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	defer sessionsReaper("test-session-state")
+
+	sessions, err := s.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %s", err)
+	}
+	for _, session := range sessions {
+		if session.Name != "test-session-state" {
+			continue
+		}
+		if time.Since(session.Created) > time.Minute || session.LastActivity.IsZero() {
+			t.Fatalf("Incorrect session times: %v, %v", session.Created, session.LastActivity)
+		}
+		if session.WindowCount != 1 || session.Attached != 0 {
+			t.Fatalf("Unexpected session state: %+v", session)
+		}
+		return
+	}
+	t.Fatalf("Can't find created session")
+}
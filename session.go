@@ -4,20 +4,28 @@
 package tmux
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
+	"time"
 )
 
 // Represents a tmux session:
 // https://github.com/tmux/tmux/wiki/Getting-Started#sessions-windows-and-panes
 type Session struct {
-	Id             int      // Session id
-	Name           string   // Session name
-	StartDirectory string   // Path to window start directory
+	Id             int      `tmux:"session_id"`   // Session id
+	Name           string   `tmux:"session_name"` // Session name
+	StartDirectory string   `tmux:"session_path"` // Path to window start directory
 	Windows        []Window // List of windows used on session initialization
+
+	Created      time.Time `tmux:"session_created"`  // Time the session was created
+	LastActivity time.Time `tmux:"session_activity"` // Time of the last activity in the session
+	Attached     int       `tmux:"session_attached"` // Number of clients attached to the session
+	Group        string    `tmux:"session_group"`    // Name of the session group
+	Grouped      bool      `tmux:"session_grouped"`  // True if the session is in a group
+	WindowCount  int       `tmux:"session_windows"`  // Number of windows in the session
+
+	server *Server // Server this session was obtained from
 }
 
 // Creates a new session object.
@@ -54,35 +62,17 @@ func (s *Session) AddWindow(window Window) {
 
 // Lists all windows in the current session.
 func (s *Session) ListWindows() ([]Window, error) {
-	args := []string{
-		"list-windows",
-		"-t", s.Name,
-		"-F", "#{window_id}:#{window_name}:#{pane_current_path}"}
+	return s.ListWindowsContext(context.Background())
+}
 
-	out, _, err := RunCmd(args)
-	if err != nil {
+// Same as ListWindows, but aborts the tmux command when ctx is done.
+func (s *Session) ListWindowsContext(ctx context.Context) ([]Window, error) {
+	windows := []Window{}
+	if err := s.server.QueryList(ctx, &windows, "list-windows", "-t", s.Name); err != nil {
 		return nil, err
 	}
-
-	outLines := strings.Split(out, "\n")
-	windows := []Window{}
-	re := regexp.MustCompile(`@([0-9]+):(.+):(.+)`)
-	for _, line := range outLines {
-		result := re.FindStringSubmatch(line)
-		if len(result) < 4 {
-			continue
-		}
-		id, err_atoi := strconv.Atoi(result[1])
-		if err_atoi != nil {
-			return nil, err_atoi
-		}
-
-		windows = append(windows, Window{
-			Name:           result[2],
-			Id:             id,
-			StartDirectory: result[3],
-			SessionName:    s.Name,
-			SessionId:      s.Id})
+	for i := range windows {
+		windows[i].server = s.server
 	}
 
 	return windows, nil
@@ -90,7 +80,7 @@ func (s *Session) ListWindows() ([]Window, error) {
 
 // Attach to existing tmux session.
 func (s *Session) AttachSession() error {
-	args := []string{}
+	args := s.server.socketArgs()
 	// If run inside tmux, switch the current session to the new one.
 	if !IsInsideTmux() {
 		args = append(args, "attach-session", "-t", s.Name)
@@ -105,15 +95,20 @@ func (s *Session) AttachSession() error {
 	return nil
 }
 
-// Detaches from the current session.
+// Detaches all clients attached to this session.
 // Detaching from the tmux session means that the client exits and detaches
 // from the outside terminal.
 // See: https://github.com/tmux/tmux/wiki/Getting-Started#attaching-and-detaching
 func (s *Session) DettachSession() error {
+	return s.DettachSessionContext(context.Background())
+}
+
+// Same as DettachSession, but aborts the tmux command when ctx is done.
+func (s *Session) DettachSessionContext(ctx context.Context) error {
 	args := []string{
 		"detach-client",
 		"-s", s.Name}
-	if err := ExecCmd(args); err != nil {
+	if _, _, err := s.server.run(ctx, args); err != nil {
 		return err
 	}
 	return nil
@@ -121,53 +116,56 @@ func (s *Session) DettachSession() error {
 
 // Creates a new window inside this session.
 func (s *Session) NewWindow(name string) (window Window, err error) {
+	return s.NewWindowContext(context.Background(), name)
+}
+
+// Same as NewWindow, but aborts the tmux command when ctx is done.
+func (s *Session) NewWindowContext(ctx context.Context, name string) (window Window, err error) {
 	args := []string{
 		"new-window",
 		"-d",
 		"-t", fmt.Sprintf("%s:", s.Name),
-		"-n", name,
-		"-F", "#{window_id}:#{window_name}", "-P"}
-	out, _, err_exec := RunCmd(args)
-	if err_exec != nil {
-		return window, err_exec
-	}
+		"-n", name}
 
-	re := regexp.MustCompile(`@([0-9]+):(.+)`)
-	result := re.FindStringSubmatch(out)
-	if len(result) < 3 {
-		return window, errors.New("Error creating new window")
+	// New window contains a single pane
+	var created struct {
+		Window
+		Pane
 	}
-	id, err_atoi := strconv.Atoi(result[1])
-	if err_atoi != nil {
-		return window, err_atoi
+	if err := s.server.queryCreate(ctx, &created, args...); err != nil {
+		return window, err
 	}
 
-	pane := Pane{
-		SessionId:   s.Id,
-		SessionName: s.Name,
-		WindowId:    id,
-		WindowName:  result[2],
-		WindowIndex: 0}
-	new_window := Window{
-		Name:        result[2],
-		Id:          id,
-		SessionName: s.Name,
-		SessionId:   s.Id,
-		Panes:       []Pane{pane}}
-	return new_window, nil
+	window = created.Window
+	window.server = s.server
+	pane := created.Pane
+	pane.server = s.server
+	window.Panes = []Pane{pane}
+	return window, nil
 }
 
 // Returns list with all panes for this session.
 func (s *Session) ListPanes() ([]Pane, error) {
-	return ListPanes([]string{"-s", "-t", s.Name})
+	return s.ListPanesContext(context.Background())
+}
+
+// Same as ListPanes, but aborts the tmux command when ctx is done.
+func (s *Session) ListPanesContext(ctx context.Context) ([]Pane, error) {
+	return s.server.listPanes(ctx, []string{"-s", "-t", s.Name})
 }
 
 // Returns a name of the attached tmux session.
 func GetAttachedSessionName() (string, error) {
+	return GetAttachedSessionNameContext(context.Background())
+}
+
+// Same as GetAttachedSessionName, but aborts the tmux command when ctx is done.
+func GetAttachedSessionNameContext(ctx context.Context) (string, error) {
 	args := []string{
 		"display-message",
 		"-p", "#S"}
-	out, _, err := RunCmd(args)
+	var server *Server
+	out, _, err := server.run(ctx, args)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,99 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Snapshot of the whole server topology obtained with a single command.
+
+package tmux
+
+import "context"
+
+// Represents the state of all sessions, windows and panes of the server at
+// the moment.
+type Snapshot struct {
+	Sessions []*SessionNode
+}
+
+// Session of the snapshot.
+type SessionNode struct {
+	Session Session
+	Windows []*WindowNode // Windows of this session ordered by index
+}
+
+// Window of the snapshot. Windows linked to several sessions are represented
+// by separate nodes in each session.
+type WindowNode struct {
+	Window  Window
+	Session *SessionNode // Session containing this window
+	Panes   []*PaneNode  // Panes of this window ordered by index
+}
+
+// Pane of the snapshot.
+type PaneNode struct {
+	Pane   Pane
+	Window *WindowNode // Window containing this pane
+}
+
+// Returns a snapshot of all sessions, windows and panes of this server. It is
+// obtained with a single list-panes command, so the state is consistent.
+func (s *Server) Snapshot() (*Snapshot, error) {
+	return s.SnapshotContext(context.Background())
+}
+
+// Same as Snapshot, but aborts the tmux command when ctx is done.
+func (s *Server) SnapshotContext(ctx context.Context) (*Snapshot, error) {
+	type row struct {
+		Session
+		Window
+		Pane
+	}
+	rows := []row{}
+	if err := s.QueryList(ctx, &rows, "list-panes", "-a"); err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{Sessions: []*SessionNode{}}
+	sessions := map[int]*SessionNode{}
+	type windowKey struct{ session, window int }
+	windows := map[windowKey]*WindowNode{}
+
+	for _, r := range rows {
+		sn, ok := sessions[r.Session.Id]
+		if !ok {
+			sn = &SessionNode{Session: r.Session, Windows: []*WindowNode{}}
+			sn.Session.server = s
+			sessions[r.Session.Id] = sn
+			snap.Sessions = append(snap.Sessions, sn)
+		}
+
+		key := windowKey{r.Session.Id, r.Window.Id}
+		wn, ok := windows[key]
+		if !ok {
+			wn = &WindowNode{Window: r.Window, Session: sn, Panes: []*PaneNode{}}
+			wn.Window.server = s
+			windows[key] = wn
+			sn.Windows = append(sn.Windows, wn)
+		}
+		if r.Pane.Active {
+			// Window fields depending on the pane, e.g. StartDirectory, are
+			// taken from the active pane, as list-windows does
+			wn.Window = r.Window
+			wn.Window.server = s
+		}
+
+		pn := &PaneNode{Pane: r.Pane, Window: wn}
+		pn.Pane.server = s
+		wn.Panes = append(wn.Panes, pn)
+	}
+
+	return snap, nil
+}
+
+// Returns the session with given name or nil if it doesn't exist.
+func (snap *Snapshot) FindSession(name string) *SessionNode {
+	for _, sn := range snap.Sessions {
+		if sn.Session.Name == name {
+			return sn
+		}
+	}
+	return nil
+}
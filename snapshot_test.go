@@ -0,0 +1,81 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	window, err := session.NewWindow("test-snapshot")
+	if err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+
+	snap, err := server.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+	sn := snap.FindSession(session.Name)
+	if sn == nil {
+		t.Fatalf("Can't find session %s", session.Name)
+	}
+	if sn.Session.Id != session.Id {
+		t.Fatalf("Incorrect session id (expected %d got %d)", session.Id, sn.Session.Id)
+	}
+	if len(sn.Windows) != 2 {
+		t.Fatalf("Expected 2 windows, got %d", len(sn.Windows))
+	}
+
+	wn := sn.Windows[1]
+	if wn.Window.Id != window.Id || wn.Window.Name != window.Name {
+		t.Fatalf("Unexpected window: %+v", wn.Window)
+	}
+	if wn.Session != sn {
+		t.Fatalf("Incorrect parent of the window")
+	}
+	if len(wn.Panes) != 1 || wn.Panes[0].Window != wn {
+		t.Fatalf("Incorrect panes of the window: %+v", wn.Panes)
+	}
+	if wn.Panes[0].Pane.ID != window.Panes[0].ID {
+		t.Fatalf("Incorrect pane id (expected %d got %d)", window.Panes[0].ID, wn.Panes[0].Pane.ID)
+	}
+}
+
+func TestSnapshotActivePane(t *testing.T) {
+	server, session := createControlServer(t)
+	defer killServer(server)
+
+	panes, err := session.ListPanes()
+	if err != nil || len(panes) != 1 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	// The split pane becomes active, the first pane stays in another directory
+	if _, err := panes[0].Split(SplitOptions{StartDirectory: "/"}); err != nil {
+		t.Fatalf("Split: %s", err)
+	}
+	windows, err := session.ListWindows()
+	if err != nil || len(windows) != 1 {
+		t.Fatalf("ListWindows: %v", err)
+	}
+	if windows[0].StartDirectory != "/" {
+		t.Fatalf("Unexpected directory of the window: %s", windows[0].StartDirectory)
+	}
+
+	snap, err := server.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %s", err)
+	}
+	wn := snap.FindSession(session.Name).Windows[0]
+	if wn.Window.StartDirectory != windows[0].StartDirectory {
+		t.Fatalf("Incorrect directory of the window (expected %s got %s)",
+			windows[0].StartDirectory, wn.Window.StartDirectory)
+	}
+	if len(wn.Panes) != 2 || wn.Panes[0].Pane.CurrentPath == "/" {
+		t.Fatalf("Unexpected panes of the window: %+v", wn.Panes)
+	}
+}
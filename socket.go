@@ -0,0 +1,85 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Resolution of tmux server sockets.
+
+package tmux
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Timeout used to check that the server is listening on a socket.
+const socketDialTimeout = time.Second
+
+// Returns a directory where tmux creates sockets for the current user. It is
+// resolved the same way as tmux does: "tmux-UID" inside $TMUX_TMPDIR, or
+// inside /tmp if the variable is not set.
+func SocketDir() string {
+	tmpdir := os.Getenv("TMUX_TMPDIR")
+	if tmpdir == "" {
+		tmpdir = "/tmp"
+	}
+	if resolved, err := filepath.EvalSymlinks(tmpdir); err == nil {
+		tmpdir = resolved
+	}
+	return filepath.Join(tmpdir, fmt.Sprintf("tmux-%d", os.Getuid()))
+}
+
+// Returns a path to the socket that tmux uses when it is started with given
+// -S socketPath and -L socketName flags. Empty values mean that the flag is
+// not set. If both are empty, the socket of the current client from $TMUX is
+// used, and then the "default" socket.
+func ResolveSocketPath(socketPath, socketName string) string {
+	if socketPath != "" {
+		return socketPath
+	}
+	if socketName == "" {
+		env := os.Getenv("TMUX")
+		if env != "" && env[0] != ',' {
+			return strings.SplitN(env, ",", 2)[0]
+		}
+		socketName = "default"
+	}
+	return filepath.Join(SocketDir(), socketName)
+}
+
+// Returns a path to the socket of this server.
+func (s *Server) ResolvedSocketPath() string {
+	return ResolveSocketPath(s.SocketPath, s.SocketName)
+}
+
+// Returns servers listening on sockets in SocketDir. Sockets left by dead
+// servers are skipped.
+func DiscoverServers() ([]*Server, error) {
+	dir := SocketDir()
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []*Server{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []*Server{}
+	for _, e := range entries {
+		if e.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		conn, err := net.DialTimeout("unix", path, socketDialTimeout)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+		servers = append(servers, NewServer(path, "", nil))
+	}
+
+	return servers, nil
+}
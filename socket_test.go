@@ -0,0 +1,65 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSocketPath(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "go-tmux")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer os.RemoveAll(tmpdir)
+	tmpdir, _ = filepath.EvalSymlinks(tmpdir)
+	t.Setenv("TMUX_TMPDIR", tmpdir)
+	t.Setenv("TMUX", "")
+
+	dir := filepath.Join(tmpdir, fmt.Sprintf("tmux-%d", os.Getuid()))
+	cases := []struct {
+		path, name, expected string
+	}{
+		{"", "", filepath.Join(dir, "default")},
+		{"", "work", filepath.Join(dir, "work")},
+		{"/run/tmux.sock", "work", "/run/tmux.sock"},
+	}
+	for _, c := range cases {
+		if got := ResolveSocketPath(c.path, c.name); got != c.expected {
+			t.Errorf("ResolveSocketPath(%q, %q): expected %s got %s", c.path, c.name, c.expected, got)
+		}
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/other,1234,0")
+	if got := ResolveSocketPath("", ""); got != "/tmp/tmux-1000/other" {
+		t.Errorf("Socket from $TMUX was ignored: %s", got)
+	}
+}
+
+func TestDiscoverServers(t *testing.T) {
+	s := NewServer("", "go-tmux-discover", nil)
+	if _, err := s.NewSession("test-discover"); err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	defer killServer(s)
+
+	servers, err := DiscoverServers()
+	if err != nil {
+		t.Fatalf("DiscoverServers: %s", err)
+	}
+	found := false
+	for _, srv := range servers {
+		if srv.SocketPath == s.ResolvedSocketPath() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("Can't find server listening on %s", s.ResolvedSocketPath())
+	}
+}
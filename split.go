@@ -0,0 +1,101 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Direction of the pane split.
+type SplitDirection int
+
+const (
+	SplitVertical   SplitDirection = iota // New pane is placed below
+	SplitHorizontal                       // New pane is placed to the right
+)
+
+// Options of the pane split. Zero value splits the pane vertically in half
+// and selects the new pane.
+type SplitOptions struct {
+	Direction      SplitDirection
+	Before         bool              // Place the new pane to the left or above
+	FullSize       bool              // Span the full window width or height
+	Size           int               // Size of the new pane in cells
+	Percent        int               // Size of the new pane in percent of the available space
+	StartDirectory string            // Working directory of the new pane
+	Environment    map[string]string // Environment variables set in the new pane
+	Command        string            // Shell command to run instead of the default shell
+	Detached       bool              // Do not select the new pane
+}
+
+// Returns arguments of split-window command.
+func (o SplitOptions) args(target string) ([]string, error) {
+	if o.Size != 0 && o.Percent != 0 {
+		return nil, errors.New("Size and Percent can't be set together")
+	}
+	if o.Size < 0 || o.Percent < 0 || o.Percent > 100 {
+		return nil, errors.New("Bad size of the new pane")
+	}
+
+	args := []string{"split-window", "-t", target}
+	if o.Direction == SplitHorizontal {
+		args = append(args, "-h")
+	} else {
+		args = append(args, "-v")
+	}
+	if o.Before {
+		args = append(args, "-b")
+	}
+	if o.FullSize {
+		args = append(args, "-f")
+	}
+	if o.Detached {
+		args = append(args, "-d")
+	}
+	if o.Size != 0 {
+		args = append(args, "-l", fmt.Sprintf("%d", o.Size))
+	}
+	if o.Percent != 0 {
+		args = append(args, "-l", fmt.Sprintf("%d%%", o.Percent))
+	}
+	if o.StartDirectory != "" {
+		args = append(args, "-c", o.StartDirectory)
+	}
+
+	names := make([]string, 0, len(o.Environment))
+	for name := range o.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "-e", name+"="+o.Environment[name])
+	}
+	if o.Command != "" {
+		args = append(args, o.Command)
+	}
+
+	return args, nil
+}
+
+// Splits the pane and returns the new one.
+func (p *Pane) Split(opts SplitOptions) (Pane, error) {
+	return p.SplitContext(context.Background(), opts)
+}
+
+// Same as Split, but aborts the tmux command when ctx is done.
+func (p *Pane) SplitContext(ctx context.Context, opts SplitOptions) (pane Pane, err error) {
+	args, err := opts.args(p.target())
+	if err != nil {
+		return pane, err
+	}
+	if err := p.server.queryCreate(ctx, &pane, args...); err != nil {
+		return pane, err
+	}
+
+	pane.server = p.server
+	return pane, nil
+}
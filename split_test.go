@@ -0,0 +1,81 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitOptionsArgs(t *testing.T) {
+	opts := SplitOptions{
+		Direction:      SplitHorizontal,
+		Before:         true,
+		Percent:        30,
+		StartDirectory: "/tmp",
+		Environment:    map[string]string{"B": "2", "A": "1"},
+		Command:        "top",
+		Detached:       true,
+	}
+	args, err := opts.args("%1")
+	if err != nil {
+		t.Fatalf("args: %s", err)
+	}
+	expected := []string{"split-window", "-t", "%1", "-h", "-b", "-d", "-l", "30%",
+		"-c", "/tmp", "-e", "A=1", "-e", "B=2", "top"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Incorrect arguments (expected %v got %v)", expected, args)
+	}
+
+	if _, err := (SplitOptions{Size: 10, Percent: 10}).args("%1"); err == nil {
+		t.Fatalf("Size and Percent were accepted together")
+	}
+}
+
+func TestPaneSplit(t *testing.T) {
+	s := NewServer("", "go-tmux-"+t.Name(), nil)
+	defer killServer(s)
+	session, err := s.NewSession("test-split")
+	if err != nil {
+		t.Fatalf("NewSession: %s", err)
+	}
+	panes, err := session.ListPanes()
+	if err != nil || len(panes) != 1 {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	pane := panes[0]
+
+	created, err := pane.Split(SplitOptions{
+		Direction:      SplitHorizontal,
+		Size:           20,
+		StartDirectory: "/tmp",
+		Detached:       true,
+	})
+	if err != nil {
+		t.Fatalf("Split: %s", err)
+	}
+	if created.ID == pane.ID || created.Width != 20 {
+		t.Fatalf("Unexpected pane: %+v", created)
+	}
+	if created.Active {
+		t.Fatalf("Detached pane was selected")
+	}
+
+	// The new pane must be usable right away. Its path is read from the
+	// process, which may not be started yet.
+	for deadline := time.Now().Add(5 * time.Second); created.CurrentPath != "/tmp"; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Incorrect path (expected %s got %s)", "/tmp", created.CurrentPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if err := created.Refresh(); err != nil {
+			t.Fatalf("Refresh: %s", err)
+		}
+	}
+	panes, _ = session.ListPanes()
+	if len(panes) != 2 {
+		t.Fatalf("Expected 2 panes, got %d", len(panes))
+	}
+}
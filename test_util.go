@@ -4,8 +4,11 @@
 package tmux
 
 import (
+	"context"
+	"net"
 	"os"
 	"strings"
+	"time"
 )
 
 // Kills sessions that contains namePattern substring in the name.
@@ -20,6 +23,21 @@ func sessionsReaper(namePattern string) {
 	}
 }
 
+// Kills the server with all its sessions and waits until it stops listening.
+func killServer(s *Server) {
+	// Executor of the server may be already closed
+	s = NewServer(s.SocketPath, s.SocketName, nil)
+	s.run(context.Background(), []string{"kill-server"})
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("unix", s.ResolvedSocketPath())
+		if err != nil {
+			return
+		}
+		conn.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // Restores the session that was active before test.
 func restoreSession() {
 	if !IsInsideTmux() {
@@ -0,0 +1,69 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+//
+// Waiting for content of panes.
+
+package tmux
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// Interval between captures of the pane while waiting for its content.
+const pollInterval = 50 * time.Millisecond
+
+// Result of waiting for the pane content.
+type WaitResult struct {
+	Match  []string // Matched text followed by submatches of the pattern
+	Screen string   // Visible content of the pane where the pattern was found
+}
+
+// Waits until the visible content of the pane matches the pattern. Returns
+// ctx.Err() if the pattern does not appear before ctx is done.
+func (p *Pane) WaitFor(ctx context.Context, pattern *regexp.Regexp) (WaitResult, error) {
+	var result WaitResult
+	args := func() ([]string, error) {
+		return CaptureOptions{}.args(p.target()), nil
+	}
+	err := p.pollCapture(ctx, args, func(out string) bool {
+		m := pattern.FindStringSubmatch(out)
+		if m == nil {
+			return false
+		}
+		result = WaitResult{Match: m, Screen: out}
+		return true
+	})
+	return result, err
+}
+
+// Waits until the text appears in the visible content of the pane. See
+// WaitFor.
+func (p *Pane) WaitForText(ctx context.Context, text string) (WaitResult, error) {
+	return p.WaitFor(ctx, regexp.MustCompile(regexp.QuoteMeta(text)))
+}
+
+// Runs the capture-pane command with arguments returned by args until done
+// returns true for its output.
+func (p *Pane) pollCapture(ctx context.Context, args func() ([]string, error), done func(out string) bool) error {
+	for {
+		a, err := args()
+		if err != nil {
+			return err
+		}
+		out, _, err := p.server.run(ctx, a)
+		if err != nil {
+			return err
+		}
+		if done(out) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaneWaitFor(t *testing.T) {
+	server, pane := createShellPane(t, "sh")
+	defer killServer(server)
+
+	if err := pane.RunCommand(`printf 'Listening on :%d\n' 8080`); err != nil {
+		t.Fatalf("RunCommand: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := pane.WaitFor(ctx, regexp.MustCompile(`Listening on :(\d+)`))
+	if err != nil {
+		t.Fatalf("WaitFor: %s", err)
+	}
+	if len(result.Match) != 2 || result.Match[1] != "8080" {
+		t.Fatalf("Unexpected match: %v", result.Match)
+	}
+	if !strings.Contains(result.Screen, "Listening on :8080\n") {
+		t.Fatalf("Unexpected screen: %q", result.Screen)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := pane.WaitForText(ctx, "never printed"); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
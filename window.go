@@ -3,7 +3,13 @@
 
 package tmux
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jubnzv/go-tmux/layout"
+)
 
 const (
 	LayoutEvenHorizontal = "even-horizontal"
@@ -16,13 +22,28 @@ const (
 // Represents a tmux window:
 // https://github.com/tmux/tmux/wiki/Getting-Started#sessions-windows-and-panes
 type Window struct {
-	Name           string
-	Id             int
-	SessionId      int
-	SessionName    string
-	StartDirectory string // Path to window working directory
+	Name           string `tmux:"window_name"`
+	Id             int    `tmux:"window_id"`
+	SessionId      int    `tmux:"session_id"`
+	SessionName    string `tmux:"session_name"`
+	StartDirectory string `tmux:"pane_current_path"` // Path to window working directory
 	Layout         string // Preset arrangements of panes
 	Panes          []Pane // List of panes used in initial window configuration
+
+	Index         int    `tmux:"window_index"`          // Index of the window in the session
+	Active        bool   `tmux:"window_active"`         // True if the window is active
+	CurrentLayout string `tmux:"window_layout"`         // Current layout description
+	VisibleLayout string `tmux:"window_visible_layout"` // Layout description ignoring zoomed panes
+	Flags         string `tmux:"window_flags"`          // Flags shown in the status line, e.g. "*Z"
+	Zoomed        bool   `tmux:"window_zoomed_flag"`    // True if the window is zoomed
+	Bell          bool   `tmux:"window_bell_flag"`      // True if the window has bell
+	Activity      bool   `tmux:"window_activity_flag"`  // True if the window has activity
+	Silence       bool   `tmux:"window_silence_flag"`   // True if the window has silence alert
+	Width         int    `tmux:"window_width"`          // Width of the window in cells
+	Height        int    `tmux:"window_height"`         // Height of the window in cells
+	PaneCount     int    `tmux:"window_panes"`          // Number of panes in the window
+
+	server *Server // Server this window was obtained from
 }
 
 // Creates a new window object.
@@ -40,7 +61,12 @@ func NewWindow(id int, name string, sessionId int, sessionName string, startDire
 
 // Returns a list with all panes for this window.
 func (w *Window) ListPanes() ([]Pane, error) {
-	return ListPanes([]string{"-t", w.Name})
+	return w.ListPanesContext(context.Background())
+}
+
+// Same as ListPanes, but aborts the tmux command when ctx is done.
+func (w *Window) ListPanesContext(ctx context.Context) ([]Pane, error) {
+	return w.server.listPanes(ctx, []string{"-t", w.Name})
 }
 
 // Adds the pane to the window configuration. This will change only in-library
@@ -52,21 +78,80 @@ func (w *Window) AddPane(pane Pane) {
 
 // Sets the window layout. The possible value can be one of the contants:
 // LayoutEvenVertical, LayoutEvenHorizontal, LayoutMainVertical,  LayoutMainHorizontal, LayoutTiled
+// or a custom layout string (see SetCustomLayout).
 // See: https://www.man7.org/linux/man-pages/man1/tmux.1.html#WINDOWS_AND_PANES
 func (w *Window) SetLayout(layout string) {
 	w.Layout = layout
 }
 
+// Sets the exact custom layout of the window. The layout must contain the same
+// number of panes as the window.
+func (w *Window) SetCustomLayout(l *layout.Cell) {
+	w.Layout = l.String()
+}
+
+// Applies the layout to the window immediately with select-layout. The layout
+// can be one of the preset layouts or a custom layout string, for example
+// generated with layout.Grid. Layout field used by configuration is left
+// unchanged, call Refresh to update CurrentLayout.
+func (w *Window) ApplyLayout(l string) error {
+	return w.ApplyLayoutContext(context.Background(), l)
+}
+
+// Same as ApplyLayout, but aborts the tmux command when ctx is done.
+func (w *Window) ApplyLayoutContext(ctx context.Context, l string) error {
+	args := []string{"select-layout", "-t", w.target(), l}
+	if _, _, err := w.server.run(ctx, args); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Returns the parsed current layout of the window.
+func (w *Window) ParseLayout() (*layout.Cell, error) {
+	return layout.Parse(w.CurrentLayout)
+}
+
+// Returns true if the layout looks like a custom layout string, which starts
+// with 4 hex digits of the checksum and a comma. Other strings, e.g. names of
+// preset layouts or their prefixes, are passed to tmux as is.
+func isCustomLayout(l string) bool {
+	if len(l) < 5 || l[4] != ',' {
+		return false
+	}
+	_, err := strconv.ParseUint(l[:4], 16, 16)
+	return err == nil
+}
+
+// Re-reads the state of this window from tmux.
+func (w *Window) Refresh() error {
+	return w.RefreshContext(context.Background())
+}
+
+// Same as Refresh, but aborts the tmux command when ctx is done.
+func (w *Window) RefreshContext(ctx context.Context) error {
+	return w.server.QueryDisplay(ctx, w.target(), w)
+}
+
+// Returns target of this window for tmux commands.
+func (w *Window) target() string {
+	return fmt.Sprintf("@%d", w.Id)
+}
+
 // Selects the window.
 func (w *Window) Select() error {
+	return w.SelectContext(context.Background())
+}
+
+// Same as Select, but aborts the tmux command when ctx is done.
+func (w *Window) SelectContext(ctx context.Context) error {
 	args := []string{
 		"select-window",
 		"-t",
-		fmt.Sprintf("@%d", w.Id),
+		w.target(),
 	}
-	_, stdErr, err := RunCmd(args)
-	if err != nil {
-		return fmt.Errorf("%v: %s", err, stdErr)
+	if _, _, err := w.server.run(ctx, args); err != nil {
+		return err
 	}
 	return nil
 }
@@ -5,6 +5,8 @@ package tmux
 
 import (
 	"testing"
+
+	"github.com/jubnzv/go-tmux/layout"
 )
 
 func TestWindowListPanes(t *testing.T) {
@@ -55,3 +57,68 @@ func TestWindowHaveSinglePaneAfterInit(t *testing.T) {
 		t.Fatalf("Window must have single pane after init (got %d)", len(panes))
 	}
 }
+
+func TestWindowRefresh(t *testing.T) {
+	s := createSession()
+	defer sessionsReaper(s.Name)
+
+	w, err := s.NewWindow("test-window-refresh")
+	if err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	if w.PaneCount != 1 || w.CurrentLayout == "" || w.Width == 0 {
+		t.Fatalf("Window state was not populated: %+v", w)
+	}
+
+	if _, _, err := RunCmd([]string{"split-window", "-d", "-t", w.target()}); err != nil {
+		t.Fatalf("split-window: %s", err)
+	}
+	if err := w.Refresh(); err != nil {
+		t.Fatalf("Refresh: %s", err)
+	}
+	if w.PaneCount != 2 {
+		t.Fatalf("Incorrect number of panes (expected 2 got %d)", w.PaneCount)
+	}
+	if w.Name != "test-window-refresh" {
+		t.Fatalf("Incorrect window name: %s", w.Name)
+	}
+}
+
+func TestWindowApplyLayout(t *testing.T) {
+	s := createSession()
+	defer sessionsReaper(s.Name)
+
+	w, err := s.NewWindow("test-window-layout")
+	if err != nil {
+		t.Fatalf("NewWindow: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := RunCmd([]string{"split-window", "-d", "-t", w.target()}); err != nil {
+			t.Fatalf("split-window: %s", err)
+		}
+	}
+
+	grid, err := layout.Grid(w.Width, w.Height, 3, 2)
+	if err != nil {
+		t.Fatalf("Grid: %s", err)
+	}
+	if err := w.ApplyLayout(grid.String()); err != nil {
+		t.Fatalf("ApplyLayout: %s", err)
+	}
+	if w.Layout != "" {
+		t.Fatalf("Layout of configuration was changed: %s", w.Layout)
+	}
+	if err := w.Refresh(); err != nil {
+		t.Fatalf("Refresh: %s", err)
+	}
+	current, err := w.ParseLayout()
+	if err != nil {
+		t.Fatalf("ParseLayout: %s", err)
+	}
+	expected, actual := grid.Panes(), current.Panes()
+	for i := range expected {
+		if expected[i].Width != actual[i].Width || expected[i].Height != actual[i].Height {
+			t.Fatalf("Layout was not applied (expected %s got %s)", grid.Body(), current.Body())
+		}
+	}
+}